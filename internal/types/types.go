@@ -10,6 +10,7 @@ package types
 import (
 	"math/big"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -23,7 +24,8 @@ type BigInt big.Int
 func (b *BigInt) UnmarshalText(text []byte) error {
 	var bigInt = new(big.Int)
 
-	if string(text) == "" {
+	// etherscan writes a zero hex quantity as a bare "0x" at times
+	if string(text) == "" || string(text) == "0x" {
 		bigInt.SetInt64(0)
 		*b = BigInt(*bigInt)
 		return nil
@@ -50,10 +52,20 @@ func (b *BigInt) MarshalText() ([]byte, error) {
 type Time time.Time
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It takes unix seconds in decimal, or as a hex quantity like "0x5b65d9c1".
 func (t *Time) UnmarshalText(text []byte) error {
-	input, err := strconv.ParseInt(string(text), 10, 64)
-	if err != nil {
-		return errors.Wrap(err, "strconv.ParseInt")
+	var input int64
+	if strings.HasPrefix(string(text), "0x") {
+		seconds, err := ParseQuantity(string(text))
+		if err != nil {
+			return err
+		}
+		input = int64(seconds)
+	} else {
+		var err error
+		if input, err = strconv.ParseInt(string(text), 10, 64); err != nil {
+			return errors.Wrap(err, "strconv.ParseInt")
+		}
 	}
 
 	var timestamp = time.Unix(input, 0)
@@ -69,3 +81,17 @@ func (t Time) Time() time.Time { return time.Time(t) }
 func (t Time) MarshalText() (text []byte, err error) {
 	return []byte(strconv.FormatInt(t.Time().Unix(), 10)), nil
 }
+
+// ParseQuantity parses a number etherscan gives as a hex quantity like "0x1b4",
+// or in decimal. An empty text or a bare "0x" is zero.
+func ParseQuantity(text string) (uint64, error) {
+	if text == "" || text == "0x" {
+		return 0, nil
+	}
+	if hex, ok := strings.CutPrefix(text, "0x"); ok {
+		n, err := strconv.ParseUint(hex, 16, 64)
+		return n, errors.Wrapf(err, "parsing hex quantity %q", text)
+	}
+	n, err := strconv.ParseUint(text, 10, 64)
+	return n, errors.Wrapf(err, "parsing quantity %q", text)
+}
@@ -53,3 +53,30 @@ func TestTime(t *testing.T) {
 		t.Fatalf("Time.MarshalText not working, got %s, want %s", textBytes, ansStr)
 	}
 }
+
+func TestHexQuantities(t *testing.T) {
+	n, err := ParseQuantity("0x1b4")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(436), n)
+
+	n, err = ParseQuantity("0x")
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = ParseQuantity("436")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(436), n)
+
+	_, err = ParseQuantity("0xzz")
+	assert.Error(t, err)
+
+	var ts Time
+	assert.NoError(t, ts.UnmarshalText([]byte("0x5b65d9c1")))
+	assert.Equal(t, int64(1533401537), ts.Time().Unix())
+
+	var b BigInt
+	assert.NoError(t, b.UnmarshalText([]byte("0x3b9aca00")))
+	assert.Equal(t, int64(1000000000), b.Int().Int64())
+	assert.NoError(t, b.UnmarshalText([]byte("0x")))
+	assert.Zero(t, b.Int().Sign())
+}
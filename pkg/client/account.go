@@ -8,8 +8,9 @@
 package client
 
 import (
-	"fmt"
+	"context"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -28,6 +29,11 @@ type MultiAccountBalanceParams struct {
 	Addresses []string `json:"address"`
 }
 
+type BalanceHistoryParams struct {
+	Address string `json:"address"`
+	BlockNo int    `json:"blockno"`
+}
+
 type TxListParams struct {
 	Address    string `json:"address"`
 	StartBlock *int   `json:"startblock,omitempty"`
@@ -37,6 +43,10 @@ type TxListParams struct {
 	Sort       string `json:"sort"`
 }
 
+type InternalTxByHashParams struct {
+	TxHash string `json:"txhash"`
+}
+
 type TokenTransferParams struct {
 	ContractAddress *string `json:"contractaddress,omitempty"`
 	Address         *string `json:"address,omitempty"`
@@ -60,6 +70,12 @@ type TokenBalanceParams struct {
 	Tag             string `json:"tag"`
 }
 
+type TokenBalanceHistoryParams struct {
+	ContractAddress string `json:"contractaddress"`
+	Address         string `json:"address"`
+	BlockNo         int    `json:"blockno"`
+}
+
 func (p AccountBalanceParams) GetUrlValues() url.Values {
 	values := url.Values{}
 	if p.Tag != "" {
@@ -82,6 +98,15 @@ func (p MultiAccountBalanceParams) GetUrlValues() url.Values {
 	return values
 }
 
+func (p BalanceHistoryParams) GetUrlValues() url.Values {
+	values := url.Values{}
+	if p.Address != "" {
+		values.Add("address", p.Address)
+	}
+	values.Add("blockno", strconv.Itoa(p.BlockNo))
+	return values
+}
+
 func (p TxListParams) GetUrlValues() url.Values {
 	values := url.Values{}
 	if p.Address != "" {
@@ -101,6 +126,14 @@ func (p TxListParams) GetUrlValues() url.Values {
 	return values
 }
 
+func (p InternalTxByHashParams) GetUrlValues() url.Values {
+	values := url.Values{}
+	if p.TxHash != "" {
+		values.Add("txhash", p.TxHash)
+	}
+	return values
+}
+
 func (p TokenTransferParams) GetUrlValues() url.Values {
 	values := url.Values{}
 	if p.ContractAddress != nil {
@@ -150,168 +183,285 @@ func (p TokenBalanceParams) GetUrlValues() url.Values {
 	return values
 }
 
-// Refactored methods
+func (p TokenBalanceHistoryParams) GetUrlValues() url.Values {
+	values := url.Values{}
+	if p.ContractAddress != "" {
+		values.Add("contractaddress", p.ContractAddress)
+	}
+	if p.Address != "" {
+		values.Add("address", p.Address)
+	}
+	values.Add("blockno", strconv.Itoa(p.BlockNo))
+	return values
+}
+
+// AccountBalance gets ether balance for a single address
 func (c *Client) AccountBalance(address string) (types.BigInt, error) {
+	return c.AccountBalanceContext(context.Background(), address)
+}
+
+// AccountBalanceContext is like AccountBalance but bound to ctx
+func (c *Client) AccountBalanceContext(ctx context.Context, address string) (types.BigInt, error) {
 	param := AccountBalanceParams{
 		Tag:     "latest",
 		Address: address,
 	}
-	body, err := c.execute("account", "balance", param.GetUrlValues())
+	result, err := call[types.BigInt](ctx, c, "account", "balance", param.GetUrlValues())
 	if err != nil {
 		return types.BigInt{}, errors.Wrap(err, "executing AccountBalance request")
 	}
-	fmt.Printf("%s\n", body.Bytes())
-	return response.ReadResponse[types.BigInt](body)
+	return result, nil
 }
 
+// MultiAccountBalance gets ether balance for multiple addresses in a single call
 func (c *Client) MultiAccountBalance(addresses ...string) ([]response.AccountBalance, error) {
+	return c.MultiAccountBalanceContext(context.Background(), addresses...)
+}
+
+// MultiAccountBalanceContext is like MultiAccountBalance but bound to ctx
+func (c *Client) MultiAccountBalanceContext(ctx context.Context, addresses ...string) ([]response.AccountBalance, error) {
+	return c.MultiAccountBalanceAtContext(ctx, "latest", addresses...)
+}
+
+// MultiAccountBalanceAt is like MultiAccountBalance, with the balances at the block tag,
+// such as "latest" or "pending"
+func (c *Client) MultiAccountBalanceAt(tag string, addresses ...string) ([]response.AccountBalance, error) {
+	return c.MultiAccountBalanceAtContext(context.Background(), tag, addresses...)
+}
+
+// MultiAccountBalanceAtContext is like MultiAccountBalanceAt but bound to ctx
+func (c *Client) MultiAccountBalanceAtContext(ctx context.Context, tag string, addresses ...string) ([]response.AccountBalance, error) {
 	param := MultiAccountBalanceParams{
-		Tag:       "latest",
+		Tag:       tag,
 		Addresses: addresses,
 	}
-	body, err := c.execute("account", "balancemulti", param.GetUrlValues())
+	result, err := call[[]response.AccountBalance](ctx, c, "account", "balancemulti", param.GetUrlValues())
 	if err != nil {
 		return []response.AccountBalance{}, errors.Wrap(err, "executing MultiAccountBalance request")
 	}
-	return response.ReadResponse[[]response.AccountBalance](body)
+	return result, nil
 }
 
-func (c *Client) NormalTxByAddress(address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.NormalTx, error) {
-	param := TxListParams{
-		Address:    address,
-		StartBlock: startBlock,
-		EndBlock:   endBlock,
-		Page:       page,
-		Offset:     offset,
-		Sort:       "asc",
-	}
-	if desc {
-		param.Sort = "desc"
+// AccountBalanceAtBlock gets ether balance for a single address as of blockNo.
+//
+// The balancehistory endpoint needs a paid plan, other keys get an error
+// matching response.ErrProOnly.
+func (c *Client) AccountBalanceAtBlock(address string, blockNo int) (types.BigInt, error) {
+	return c.AccountBalanceAtBlockContext(context.Background(), address, blockNo)
+}
+
+// AccountBalanceAtBlockContext is like AccountBalanceAtBlock but bound to ctx
+func (c *Client) AccountBalanceAtBlockContext(ctx context.Context, address string, blockNo int) (types.BigInt, error) {
+	param := BalanceHistoryParams{
+		Address: address,
+		BlockNo: blockNo,
 	}
-	body, err := c.execute("account", "txlist", param.GetUrlValues())
+	result, err := call[types.BigInt](ctx, c, "account", "balancehistory", param.GetUrlValues())
 	if err != nil {
-		return []response.NormalTx{}, errors.Wrap(err, "executing NormalTxByAddress request")
+		return types.BigInt{}, errors.Wrap(err, "executing AccountBalanceAtBlock request")
 	}
-	return response.ReadResponse[[]response.NormalTx](body)
+	return result, nil
+}
+
+// NormalTxByAddress gets a list of "normal" tx by address
+//
+// startBlock and endBlock can be nil
+//
+// if desc is true, result will be sorted in blockNum descendant order.
+func (c *Client) NormalTxByAddress(address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.NormalTx, error) {
+	return c.NormalTxByAddressContext(context.Background(), address, startBlock, endBlock, page, offset, desc)
+}
+
+// NormalTxByAddressContext is like NormalTxByAddress but bound to ctx
+func (c *Client) NormalTxByAddressContext(ctx context.Context, address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.NormalTx, error) {
+	params := txListParams(address, startBlock, endBlock, page, offset, desc)
+	return accountList[[]response.NormalTx](ctx, c, "txlist", "NormalTxByAddress", params.GetUrlValues())
 }
 
+// InternalTxByAddress gets a list of "internal" tx by address
+//
+// startBlock and endBlock can be nil
+//
+// if desc is true, result will be sorted in descendant order.
 func (c *Client) InternalTxByAddress(address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.InternalTx, error) {
+	return c.InternalTxByAddressContext(context.Background(), address, startBlock, endBlock, page, offset, desc)
+}
+
+// InternalTxByAddressContext is like InternalTxByAddress but bound to ctx
+func (c *Client) InternalTxByAddressContext(ctx context.Context, address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.InternalTx, error) {
+	params := txListParams(address, startBlock, endBlock, page, offset, desc)
+	return accountList[[]response.InternalTx](ctx, c, "txlistinternal", "InternalTxByAddress", params.GetUrlValues())
+}
+
+// InternalTxByHash gets the "internal" txs of the tx with hash txHash.
+//
+// Etherscan leaves the hash out of these, it is filled in with txHash.
+func (c *Client) InternalTxByHash(txHash string) ([]response.InternalTx, error) {
+	return c.InternalTxByHashContext(context.Background(), txHash)
+}
+
+// InternalTxByHashContext is like InternalTxByHash but bound to ctx
+func (c *Client) InternalTxByHashContext(ctx context.Context, txHash string) ([]response.InternalTx, error) {
+	if txHash == "" {
+		return []response.InternalTx{}, errors.Wrap(response.ErrInvalidParams, "tx hash is required")
+	}
+	param := InternalTxByHashParams{TxHash: txHash}
+	result, err := call[[]response.InternalTx](ctx, c, "account", "txlistinternal", param.GetUrlValues())
+	if err != nil {
+		return []response.InternalTx{}, errors.Wrap(err, "executing InternalTxByHash request")
+	}
+	// result may be shared with coalesced callers, fill in a copy
+	result = slices.Clone(result)
+	for i := range result {
+		if result[i].Hash == "" {
+			result[i].Hash = txHash
+		}
+	}
+	return result, nil
+}
+
+// InternalTxByBlockRange gets a list of "internal" tx of any address
+// between startBlock and endBlock, both included.
+//
+// if desc is true, result will be sorted in descendant order.
+func (c *Client) InternalTxByBlockRange(startBlock, endBlock int, page int, offset int, desc bool) ([]response.InternalTx, error) {
+	return c.InternalTxByBlockRangeContext(context.Background(), startBlock, endBlock, page, offset, desc)
+}
+
+// InternalTxByBlockRangeContext is like InternalTxByBlockRange but bound to ctx
+func (c *Client) InternalTxByBlockRangeContext(ctx context.Context, startBlock, endBlock int, page int, offset int, desc bool) ([]response.InternalTx, error) {
 	param := TxListParams{
-		Address:    address,
-		StartBlock: startBlock,
-		EndBlock:   endBlock,
+		StartBlock: &startBlock,
+		EndBlock:   &endBlock,
 		Page:       page,
 		Offset:     offset,
-		Sort:       "asc",
-	}
-	if desc {
-		param.Sort = "desc"
+		Sort:       sortOrder(desc),
+	}.withDefaults()
+	if err := validateRange(param.StartBlock, param.EndBlock, param.Page, param.Offset, param.Sort); err != nil {
+		return []response.InternalTx{}, errors.Wrap(err, "validating InternalTxByBlockRange params")
 	}
-
-	body, err := c.execute("account", "txlistinternal", param.GetUrlValues())
+	result, err := call[[]response.InternalTx](ctx, c, "account", "txlistinternal", listValues(param.GetUrlValues()))
 	if err != nil {
-		return []response.InternalTx{}, errors.Wrap(err, "executing InternalTxByAddress request")
+		return []response.InternalTx{}, errors.Wrap(err, "executing InternalTxByBlockRange request")
 	}
-	return response.ReadResponse[[]response.InternalTx](body)
+	return result, nil
+}
+
+// BeaconWithdrawals gets a list of beacon chain withdrawals to address,
+// which credit it without any tx. Amounts are in wei.
+//
+// startBlock and endBlock can be nil
+//
+// if desc is true, result will be sorted in descendant order.
+func (c *Client) BeaconWithdrawals(address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.BeaconWithdrawal, error) {
+	return c.BeaconWithdrawalsContext(context.Background(), address, startBlock, endBlock, page, offset, desc)
 }
 
+// BeaconWithdrawalsContext is like BeaconWithdrawals but bound to ctx
+func (c *Client) BeaconWithdrawalsContext(ctx context.Context, address string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.BeaconWithdrawal, error) {
+	return c.BeaconWithdrawalListContext(ctx, txListParams(address, startBlock, endBlock, page, offset, desc))
+}
+
+// ERC20Transfers get a list of "erc20 - token transfer events" by
+// contract address and/or from/to address.
+//
+// leave undesired condition to nil.
 func (c *Client) ERC20Transfers(contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.ERC20Transfer, error) {
-	param := TokenTransferParams{
-		ContractAddress: contractAddress,
-		Address:         address,
-		StartBlock:      startBlock,
-		EndBlock:        endBlock,
-		Page:            page,
-		Offset:          offset,
-		Sort:            "asc",
-	}
-	if desc {
-		param.Sort = "desc"
-	}
-	body, err := c.execute("account", "tokentx", param.GetUrlValues())
-	if err != nil {
-		return []response.ERC20Transfer{}, errors.Wrap(err, "executing ERC20Transfers request")
-	}
-	return response.ReadResponse[[]response.ERC20Transfer](body)
+	return c.ERC20TransfersContext(context.Background(), contractAddress, address, startBlock, endBlock, page, offset, desc)
 }
 
+// ERC20TransfersContext is like ERC20Transfers but bound to ctx
+func (c *Client) ERC20TransfersContext(ctx context.Context, contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.ERC20Transfer, error) {
+	params := tokenTransferParams(contractAddress, address, startBlock, endBlock, page, offset, desc)
+	return accountList[[]response.ERC20Transfer](ctx, c, "tokentx", "ERC20Transfers", params.GetUrlValues())
+}
+
+// ERC721Transfers get a list of "erc721 - token transfer events" by
+// contract address and/or from/to address.
+//
+// leave undesired condition to nil.
 func (c *Client) ERC721Transfers(contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.ERC721Transfer, error) {
-	param := TokenTransferParams{
-		ContractAddress: contractAddress,
-		Address:         address,
-		StartBlock:      startBlock,
-		EndBlock:        endBlock,
-		Page:            page,
-		Offset:          offset,
-		Sort:            "asc",
-	}
-	if desc {
-		param.Sort = "desc"
-	}
-	body, err := c.execute("account", "tokennfttx", param.GetUrlValues())
-	if err != nil {
-		return []response.ERC721Transfer{}, errors.Wrap(err, "executing ERC721Transfers request")
-	}
-	return response.ReadResponse[[]response.ERC721Transfer](body)
+	return c.ERC721TransfersContext(context.Background(), contractAddress, address, startBlock, endBlock, page, offset, desc)
+}
+
+// ERC721TransfersContext is like ERC721Transfers but bound to ctx
+func (c *Client) ERC721TransfersContext(ctx context.Context, contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.ERC721Transfer, error) {
+	params := tokenTransferParams(contractAddress, address, startBlock, endBlock, page, offset, desc)
+	return accountList[[]response.ERC721Transfer](ctx, c, "tokennfttx", "ERC721Transfers", params.GetUrlValues())
 }
 
+// ERC1155Transfers get a list of "erc1155 - token transfer events" by
+// contract address and/or from/to address.
+//
+// leave undesired condition to nil.
 func (c *Client) ERC1155Transfers(contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.ERC1155Transfer, error) {
-	param := TokenTransferParams{
-		ContractAddress: contractAddress,
-		Address:         address,
-		StartBlock:      startBlock,
-		EndBlock:        endBlock,
-		Page:            page,
-		Offset:          offset,
-		Sort:            "asc",
-	}
-	if desc {
-		param.Sort = "desc"
-	}
-	body, err := c.execute("account", "token1155tx", param.GetUrlValues())
-	if err != nil {
-		return []response.ERC1155Transfer{}, errors.Wrap(err, "executing ERC1155Transfers request")
-	}
-	return response.ReadResponse[[]response.ERC1155Transfer](body)
+	return c.ERC1155TransfersContext(context.Background(), contractAddress, address, startBlock, endBlock, page, offset, desc)
+}
+
+// ERC1155TransfersContext is like ERC1155Transfers but bound to ctx
+func (c *Client) ERC1155TransfersContext(ctx context.Context, contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) ([]response.ERC1155Transfer, error) {
+	params := tokenTransferParams(contractAddress, address, startBlock, endBlock, page, offset, desc)
+	return accountList[[]response.ERC1155Transfer](ctx, c, "token1155tx", "ERC1155Transfers", params.GetUrlValues())
 }
 
+// BlocksMinedByAddress gets list of blocks mined by address
 func (c *Client) BlocksMinedByAddress(address string, page int, offset int) ([]response.MinedBlock, error) {
-	param := MinedBlockParams{
-		Address:   address,
-		BlockType: "blocks",
-		Page:      page,
-		Offset:    offset,
-	}
-	body, err := c.execute("account", "getminedblocks", param.GetUrlValues())
-	if err != nil {
-		return []response.MinedBlock{}, errors.Wrap(err, "executing BlocksMinedByAddress request")
-	}
-	return response.ReadResponse[[]response.MinedBlock](body)
+	return c.BlocksMinedByAddressContext(context.Background(), address, page, offset)
 }
 
+// BlocksMinedByAddressContext is like BlocksMinedByAddress but bound to ctx
+func (c *Client) BlocksMinedByAddressContext(ctx context.Context, address string, page int, offset int) ([]response.MinedBlock, error) {
+	params := MinedBlockParams{Address: address, BlockType: BlockTypeBlocks, Page: page, Offset: offset}
+	return accountList[[]response.MinedBlock](ctx, c, "getminedblocks", "BlocksMinedByAddress", params.GetUrlValues())
+}
+
+// UnclesMinedByAddress gets list of uncles mined by address
 func (c *Client) UnclesMinedByAddress(address string, page int, offset int) ([]response.MinedBlock, error) {
-	param := MinedBlockParams{
-		Address:   address,
-		BlockType: "uncles",
-		Page:      page,
-		Offset:    offset,
-	}
-	body, err := c.execute("account", "getminedblocks", param.GetUrlValues())
-	if err != nil {
-		return []response.MinedBlock{}, errors.Wrap(err, "executing UnclesMinedByAddress request")
-	}
-	return response.ReadResponse[[]response.MinedBlock](body)
+	return c.UnclesMinedByAddressContext(context.Background(), address, page, offset)
+}
+
+// UnclesMinedByAddressContext is like UnclesMinedByAddress but bound to ctx
+func (c *Client) UnclesMinedByAddressContext(ctx context.Context, address string, page int, offset int) ([]response.MinedBlock, error) {
+	params := MinedBlockParams{Address: address, BlockType: BlockTypeUncles, Page: page, Offset: offset}
+	return accountList[[]response.MinedBlock](ctx, c, "getminedblocks", "UnclesMinedByAddress", params.GetUrlValues())
 }
 
+// TokenBalance get erc20-token account balance of address for contractAddress
 func (c *Client) TokenBalance(contractAddress, address string) (types.BigInt, error) {
+	return c.TokenBalanceContext(context.Background(), contractAddress, address)
+}
+
+// TokenBalanceContext is like TokenBalance but bound to ctx
+func (c *Client) TokenBalanceContext(ctx context.Context, contractAddress, address string) (types.BigInt, error) {
 	param := TokenBalanceParams{
 		ContractAddress: contractAddress,
 		Address:         address,
 		Tag:             "latest",
 	}
-	body, err := c.execute("account", "tokenbalance", param.GetUrlValues())
+	result, err := call[types.BigInt](ctx, c, "account", "tokenbalance", param.GetUrlValues())
 	if err != nil {
 		return types.BigInt{}, errors.Wrap(err, "executing TokenBalance request")
 	}
-	return response.ReadResponse[types.BigInt](body)
+	return result, nil
+}
+
+// TokenBalanceAtBlock get erc20-token account balance of address for contractAddress as of blockNo.
+//
+// The tokenbalancehistory endpoint needs a paid plan, see AccountBalanceAtBlock.
+func (c *Client) TokenBalanceAtBlock(contractAddress, address string, blockNo int) (types.BigInt, error) {
+	return c.TokenBalanceAtBlockContext(context.Background(), contractAddress, address, blockNo)
+}
+
+// TokenBalanceAtBlockContext is like TokenBalanceAtBlock but bound to ctx
+func (c *Client) TokenBalanceAtBlockContext(ctx context.Context, contractAddress, address string, blockNo int) (types.BigInt, error) {
+	param := TokenBalanceHistoryParams{
+		ContractAddress: contractAddress,
+		Address:         address,
+		BlockNo:         blockNo,
+	}
+	result, err := call[types.BigInt](ctx, c, "account", "tokenbalancehistory", param.GetUrlValues())
+	if err != nil {
+		return types.BigInt{}, errors.Wrap(err, "executing TokenBalanceAtBlock request")
+	}
+	return result, nil
 }
@@ -1,9 +1,14 @@
 package client
 
 import (
+	"fmt"
+	"net/http"
 	"net/url"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -253,3 +258,195 @@ func TestTokenBalanceParams_GetUrlValues(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceHistoryParams_GetUrlValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   BalanceHistoryParams
+		expected url.Values
+	}{
+		{
+			name:     "empty params",
+			params:   BalanceHistoryParams{},
+			expected: url.Values{"blockno": []string{"0"}},
+		},
+		{
+			name: "full params",
+			params: BalanceHistoryParams{
+				Address: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+				BlockNo: 8000000,
+			},
+			expected: url.Values{
+				"address": []string{"0x742d35Cc6634C0532925a3b844Bc454e4438f44e"},
+				"blockno": []string{"8000000"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.params.GetUrlValues()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestTokenBalanceHistoryParams_GetUrlValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   TokenBalanceHistoryParams
+		expected url.Values
+	}{
+		{
+			name:     "empty params",
+			params:   TokenBalanceHistoryParams{},
+			expected: url.Values{"blockno": []string{"0"}},
+		},
+		{
+			name: "full params",
+			params: TokenBalanceHistoryParams{
+				ContractAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+				Address:         "0x742d35Cc6634C0532925a3b844Bc454e4438f44f",
+				BlockNo:         8000000,
+			},
+			expected: url.Values{
+				"contractaddress": []string{"0x742d35Cc6634C0532925a3b844Bc454e4438f44e"},
+				"address":         []string{"0x742d35Cc6634C0532925a3b844Bc454e4438f44f"},
+				"blockno":         []string{"8000000"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.params.GetUrlValues()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestClient_AccountBalanceAtBlock(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "balancehistory", q.Get("action"))
+		assert.Equal(t, "8000000", q.Get("blockno"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"610538078574012392316"}`)
+	})
+	balance, err := c.AccountBalanceAtBlock("0x1", 8000000)
+	assert.NoError(t, err)
+	assert.Equal(t, "610538078574012392316", balance.Int().String())
+
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Sorry, it looks like you are trying to access an API Pro endpoint. Contact us to upgrade to API Pro."}`)
+	})
+	_, err = c.AccountBalanceAtBlock("0x1", 8000000)
+	assert.ErrorIs(t, err, response.ErrProOnly)
+	assert.Equal(t, ClassAPI, Classify(err))
+}
+
+func TestClient_MultiAccountBalanceAt(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "pending", r.URL.Query().Get("tag"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"account":"0x1","balance":"42"}]}`)
+	})
+	balances, err := c.MultiAccountBalanceAt("pending", "0x1")
+	assert.NoError(t, err)
+	assert.Len(t, balances, 1)
+}
+
+func TestClient_TokenBalanceAtBlock(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "tokenbalancehistory", q.Get("action"))
+		assert.Equal(t, "0xc", q.Get("contractaddress"))
+		assert.Equal(t, "0x1", q.Get("address"))
+		assert.Equal(t, "8000000", q.Get("blockno"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"135499"}`)
+	})
+	balance, err := c.TokenBalanceAtBlock("0xc", "0x1", 8000000)
+	assert.NoError(t, err)
+	assert.Equal(t, "135499", balance.Int().String())
+}
+
+func TestClient_InternalTxByHash(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "txlistinternal", q.Get("action"))
+		assert.Equal(t, "0xabc", q.Get("txhash"))
+		assert.Empty(t, q.Get("address"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"1743059","timeStamp":"1466489498","from":"0x2cac6e4b11d6b58f6d3c1c9d5fe8faa89f60e5a2","to":"0x66a1c3eaf0f1ffc28d209c0763ed0ca614f3b002","value":"7106740000000000","contractAddress":"","input":"","type":"call","gas":"2300","gasUsed":"0","isError":"0","errCode":""}]}`)
+	})
+	txs, err := c.InternalTxByHash("0xabc")
+	assert.NoError(t, err)
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, "0xabc", txs[0].Hash)
+		assert.Equal(t, 1743059, txs[0].BlockNumber)
+	}
+
+	_, err = c.InternalTxByHash("")
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+}
+
+func TestClient_InternalTxByBlockRange(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "txlistinternal", q.Get("action"))
+		assert.Empty(t, q.Get("address"))
+		assert.Equal(t, "13481773", q.Get("startblock"))
+		assert.Equal(t, "13491773", q.Get("endblock"))
+		assert.Equal(t, "1", q.Get("page"))
+		assert.Equal(t, "10", q.Get("offset"))
+		assert.Equal(t, "desc", q.Get("sort"))
+		fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+	})
+	txs, err := c.InternalTxByBlockRange(13481773, 13491773, 0, 10, true)
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+
+	_, err = c.InternalTxByBlockRange(13491773, 13481773, 1, 10, false)
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+}
+
+func TestClient_BeaconWithdrawals(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "txsBeaconWithdrawal", q.Get("action"))
+		assert.Equal(t, "0x1", q.Get("address"))
+		assert.Equal(t, "17000000", q.Get("startblock"))
+		assert.Equal(t, "1", q.Get("page"))
+		assert.Equal(t, "100", q.Get("offset"))
+		assert.Equal(t, "asc", q.Get("sort"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"withdrawalIndex":"13","validatorIndex":"117823","address":"0x1","amount":"3402931175","blockNumber":"17034877","timestamp":"1681338599"}]}`)
+	})
+	startBlock := 17000000
+	withdrawals, err := c.BeaconWithdrawals("0x1", &startBlock, nil, 1, 100, false)
+	assert.NoError(t, err)
+	if assert.Len(t, withdrawals, 1) {
+		assert.Equal(t, "3402931175000000000", withdrawals[0].Amount.Int().String())
+	}
+
+	_, err = c.BeaconWithdrawalList(TxListParams{})
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+}
+
+func TestClient_InternalTxByHash_Coalesced(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"1743059","timeStamp":"1466489498","value":"1"}]}`)
+	})
+	c.flights = newFlightGroup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			txs, err := c.InternalTxByHash("0xabc")
+			assert.NoError(t, err)
+			if assert.Len(t, txs, 1) {
+				assert.Equal(t, "0xabc", txs[0].Hash)
+			}
+		}()
+	}
+	wg.Wait()
+}
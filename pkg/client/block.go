@@ -8,6 +8,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -40,36 +41,41 @@ func (p BlockNumberParams) GetUrlValues() url.Values {
 
 // BlockReward gets block and uncle rewards by block number
 func (c *Client) BlockReward(blockNum int) (response.BlockRewards, error) {
+	return c.BlockRewardContext(context.Background(), blockNum)
+}
+
+// BlockRewardContext is like BlockReward but bound to ctx
+func (c *Client) BlockRewardContext(ctx context.Context, blockNum int) (response.BlockRewards, error) {
 	param := BlockRewardParams{
 		BlockNo: blockNum,
 	}
 
-	body, err := c.execute("block", "getblockreward", param.GetUrlValues())
+	result, err := call[response.BlockRewards](ctx, c, "block", "getblockreward", param.GetUrlValues())
 	if err != nil {
 		return response.BlockRewards{}, errors.Wrap(err, "executing BlockReward request")
 	}
-	return response.ReadResponse[response.BlockRewards](body)
+	return result, nil
 }
 
 // BlockNumber gets the closest block number by UNIX timestamp
 //
 // valid closest option: before, after
 func (c *Client) BlockNumber(timestamp int64, closest string) (int, error) {
+	return c.BlockNumberContext(context.Background(), timestamp, closest)
+}
+
+// BlockNumberContext is like BlockNumber but bound to ctx
+func (c *Client) BlockNumberContext(ctx context.Context, timestamp int64, closest string) (int, error) {
 	param := BlockNumberParams{
 		Timestamp: timestamp,
 		Closest:   closest,
 	}
 
-	body, err := c.execute("block", "getblocknobytime", param.GetUrlValues())
+	blockNumberStr, err := call[string](ctx, c, "block", "getblocknobytime", param.GetUrlValues())
 	if err != nil {
 		return 0, errors.Wrap(err, "executing BlockNumber request")
 	}
 
-	blockNumberStr, err := response.ReadResponse[string](body)
-	if err != nil {
-		return 0, errors.Wrap(err, "reading response")
-	}
-
 	blockNumber, err := strconv.Atoi(blockNumberStr)
 	if err != nil {
 		return 0, fmt.Errorf("parsing block number %q: %w", blockNumberStr, err)
@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Cache stores raw responses of successful requests.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under key, if any and not expired
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl, zero ttl means forever
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// CachePolicy decides how long a response stays cached,
+// depending on whether the data it covers can still change.
+type CachePolicy struct {
+	// ConfirmationDepth is how many blocks below the head a block is considered final
+	ConfirmationDepth int
+	// HeadTTL for responses touching non-final blocks or "latest" state,
+	// zero means such responses are not cached
+	HeadTTL time.Duration
+	// FinalTTL for responses covering final blocks only, zero means forever
+	FinalTTL time.Duration
+	// HeadRefresh is how long the head block, which tells final blocks apart,
+	// is reused before being looked up again. Zero means DefaultHeadRefresh.
+	HeadRefresh time.Duration
+}
+
+// DefaultHeadRefresh is about the time it takes to mine a block on mainnet
+const DefaultHeadRefresh = 12 * time.Second
+
+// DefaultCachePolicy caches final data forever and anything else for 15 seconds
+var DefaultCachePolicy = CachePolicy{
+	ConfirmationDepth: 64,
+	HeadTTL:           15 * time.Second,
+}
+
+// immutableActions never change once they succeed, whatever the block range.
+// Not contract/getsourcecode: it succeeds for unverified contracts too,
+// and the implementation of a proxy changes on upgrade.
+var immutableActions = map[string]bool{
+	"contract/getabi": true,
+}
+
+// blockBoundParams name the parameters holding the highest block a request covers
+var blockBoundParams = []string{"endblock", "toBlock", "blockno"}
+
+// cacheKey identifies a request by chain, module, action and parameters, but not by API key
+func (c *Client) cacheKey(module, action string, values url.Values) string {
+	values = cloneValues(values)
+	values.Del("apikey")
+	return strconv.Itoa(c.chain.ID()) + "/" + module + "/" + action + "?" + values.Encode()
+}
+
+// cacheTTL tells how long the response to a request may be cached,
+// false when it should not be cached at all.
+func (c *Client) cacheTTL(ctx context.Context, module, action string, values url.Values) (time.Duration, bool) {
+	policy := c.cachePolicy
+	if immutableActions[module+"/"+action] {
+		return policy.FinalTTL, true
+	}
+
+	bound := -1
+	for _, param := range blockBoundParams {
+		if block, err := strconv.Atoi(values.Get(param)); err == nil && block > bound {
+			bound = block
+		}
+	}
+	if bound >= 0 {
+		if head, err := c.head(ctx); err == nil && bound <= head-policy.ConfirmationDepth {
+			return policy.FinalTTL, true
+		}
+	}
+
+	return policy.HeadTTL, policy.HeadTTL > 0
+}
+
+type noCacheKey struct{}
+
+// head returns the latest block number, looked up at most once per HeadRefresh.
+// Concurrent lookups share a single request.
+func (c *Client) head(ctx context.Context) (int, error) {
+	refresh := c.cachePolicy.HeadRefresh
+	if refresh <= 0 {
+		refresh = DefaultHeadRefresh
+	}
+
+	c.headMu.Lock()
+	block, at := c.headBlock, c.headAt
+	c.headMu.Unlock()
+	if !at.IsZero() && time.Since(at) < refresh {
+		return block, nil
+	}
+
+	outcome, err := c.heads.do(ctx, "head", func(ctx context.Context) (interface{}, error) {
+		block, err := c.BlockNumberContext(context.WithValue(ctx, noCacheKey{}, true), time.Now().Unix(), "before")
+		if err != nil {
+			return nil, err
+		}
+		c.headMu.Lock()
+		c.headBlock, c.headAt = block, time.Now()
+		c.headMu.Unlock()
+		return block, nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "looking up head block")
+	}
+	return outcome.(int), nil
+}
+
+// cacheable tells whether the cache applies to requests made with ctx
+func (c *Client) cacheable(ctx context.Context) bool {
+	return c.cache != nil && ctx.Value(noCacheKey{}) == nil
+}
+
+// MemoryCache is an in-process Cache. Expired entries are dropped on access.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]memoryEntry{}}
+}
+
+// Get implements Cache
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set implements Cache
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	m.entries[key] = entry
+}
+
+// FileCache is a Cache keeping one file per entry in a directory,
+// so finalized data survives restarts.
+//
+// Each file starts with the expiry as unix nanoseconds, 0 for never, on its own line.
+type FileCache struct {
+	dir string
+}
+
+// NewFileCache creates a FileCache in dir, creating the directory if needed
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "creating cache directory")
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Cache
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	path := f.path(key)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	header, value, ok := bytes.Cut(content, []byte("\n"))
+	if !ok {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(string(header), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if expires != 0 && time.Now().UnixNano() > expires {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements Cache. Write errors are ignored, the entry is just not cached.
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	var expires int64
+	if ttl > 0 {
+		expires = time.Now().Add(ttl).UnixNano()
+	}
+
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(strconv.FormatInt(expires, 10) + "\n")
+	if err == nil {
+		_, err = tmp.Write(value)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		_ = os.Rename(tmp.Name(), f.path(key))
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache()
+
+	c.Set("forever", []byte("a"), 0)
+	c.Set("expired", []byte("b"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	value, ok := c.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), value)
+
+	_, ok = c.Get("expired")
+	assert.False(t, ok)
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestFileCache(t *testing.T) {
+	c, err := NewFileCache(t.TempDir())
+	assert.NoError(t, err)
+
+	c.Set("forever", []byte("a\nb"), 0)
+	c.Set("expired", []byte("b"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	value, ok := c.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a\nb"), value)
+
+	_, ok = c.Get("expired")
+	assert.False(t, ok)
+}
+
+func TestClient_cacheKey(t *testing.T) {
+	c := NewClient(1, "abc123")
+
+	a := c.cacheKey("account", "txlist", url.Values{"address": {"0x1"}, "page": {"1"}, "apikey": {"abc123"}})
+	b := c.cacheKey("account", "txlist", url.Values{"page": {"1"}, "address": {"0x1"}})
+	assert.Equal(t, "1/account/txlist?address=0x1&page=1", a)
+	assert.Equal(t, a, b)
+}
+
+func TestClient_Cache(t *testing.T) {
+	calls := map[string]int{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		action := r.URL.Query().Get("action")
+		calls[action]++
+		switch action {
+		case "getblocknobytime":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"1000"}`)
+		case "getabi":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"[]"}`)
+		default:
+			fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+		}
+	})
+	c.cache = NewMemoryCache()
+	c.cachePolicy = CachePolicy{ConfirmationDepth: 10, HeadTTL: time.Hour}
+
+	final, head := 900, 995
+	for i := 0; i < 3; i++ {
+		_, err := c.ContractABI("0x0000000000000000000000000000000000000000")
+		assert.NoError(t, err)
+		_, err = c.NormalTxByAddress("0x0000000000000000000000000000000000000000", nil, &final, 1, 10, false)
+		assert.NoError(t, err)
+		_, err = c.NormalTxByAddress("0x0000000000000000000000000000000000000000", nil, &head, 1, 10, false)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{"getabi": 1, "txlist": 2, "getblocknobytime": 1}, calls)
+	assert.Equal(t, 1000, c.headBlock)
+
+	ttl, ok := c.cacheTTL(context.Background(), "account", "txlist", url.Values{"endblock": {"900"}})
+	assert.True(t, ok)
+	assert.Zero(t, ttl, "final data is cached forever")
+
+	ttl, _ = c.cacheTTL(context.Background(), "account", "txlist", url.Values{"endblock": {"995"}})
+	assert.Equal(t, time.Hour, ttl)
+}
+
+func TestClient_cacheTTL_SourceCode(t *testing.T) {
+	c := NewClient(1, "abc123")
+
+	ttl, ok := c.cacheTTL(context.Background(), "contract", "getsourcecode", url.Values{"address": {"0x1"}})
+	assert.True(t, ok)
+	assert.Equal(t, DefaultCachePolicy.HeadTTL, ttl, "verification and proxy upgrades change the source")
+}
+
+func TestClient_head(t *testing.T) {
+	var lookups atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") == "getblocknobytime" {
+			lookups.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"1000"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+	})
+	c.cache = NewMemoryCache()
+	// no HeadTTL, the head is still reused for DefaultHeadRefresh
+	c.cachePolicy = CachePolicy{ConfirmationDepth: 12}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(endBlock int) {
+			defer wg.Done()
+			_, err := c.NormalTxByAddress("0x0000000000000000000000000000000000000000", nil, &endBlock, 1, 10, false)
+			assert.NoError(t, err)
+		}(900 + i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		endBlock := 800 + i
+		_, err := c.NormalTxByAddress("0x0000000000000000000000000000000000000000", nil, &endBlock, 1, 10, false)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), lookups.Load())
+}
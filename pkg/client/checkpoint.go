@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// SyncState is how far a Syncer got for one (chain, address, action)
+type SyncState struct {
+	// Block is the last block whose records were all handed out
+	Block int `json:"block"`
+	// Seen counts the records handed out per block and record id,
+	// for the blocks a later sync fetches again
+	Seen map[int]map[string]int `json:"seen,omitempty"`
+	// Unconfirmed are the records handed out that were not final yet,
+	// see Syncer.ConfirmationDepth
+	Unconfirmed []UnconfirmedRecord `json:"unconfirmed,omitempty"`
+}
+
+// UnconfirmedRecord is a record handed out before its block was final
+type UnconfirmedRecord struct {
+	ID        string          `json:"id"`
+	Block     int             `json:"block"`
+	BlockHash string          `json:"blockHash,omitempty"`
+	Record    json.RawMessage `json:"record"`
+}
+
+// Checkpoint stores SyncStates by sync key
+type Checkpoint interface {
+	// Load returns the state saved under key, false if there is none
+	Load(key string) (SyncState, bool, error)
+	Save(key string, state SyncState) error
+}
+
+// MemoryCheckpoint is an in-process Checkpoint
+type MemoryCheckpoint struct {
+	mu     sync.Mutex
+	states map[string][]byte
+}
+
+// NewMemoryCheckpoint creates an empty MemoryCheckpoint
+func NewMemoryCheckpoint() *MemoryCheckpoint {
+	return &MemoryCheckpoint{states: map[string][]byte{}}
+}
+
+// Load implements Checkpoint
+func (m *MemoryCheckpoint) Load(key string) (SyncState, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var state SyncState
+	content, ok := m.states[key]
+	if !ok {
+		return state, false, nil
+	}
+	// states are kept encoded so callers never share the Seen maps
+	if err := json.Unmarshal(content, &state); err != nil {
+		return state, false, errors.Wrapf(err, "decoding checkpoint %s", key)
+	}
+	return state, true, nil
+}
+
+// Save implements Checkpoint
+func (m *MemoryCheckpoint) Save(key string, state SyncState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrapf(err, "encoding checkpoint %s", key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[key] = content
+	return nil
+}
+
+// FileCheckpoint is a Checkpoint keeping every state in a single JSON file,
+// an object of SyncStates by sync key. The file is replaced atomically on Save.
+type FileCheckpoint struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpoint creates a FileCheckpoint stored at path, creating its directory if needed
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "creating checkpoint directory")
+	}
+	return &FileCheckpoint{path: path}, nil
+}
+
+func (f *FileCheckpoint) read() (map[string]SyncState, error) {
+	states := map[string]SyncState{}
+	content, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading checkpoint file")
+	}
+	if err := json.Unmarshal(content, &states); err != nil {
+		return nil, errors.Wrapf(err, "decoding checkpoint file %s", f.path)
+	}
+	return states, nil
+}
+
+// Load implements Checkpoint
+func (f *FileCheckpoint) Load(key string) (SyncState, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states, err := f.read()
+	if err != nil {
+		return SyncState{}, false, err
+	}
+	state, ok := states[key]
+	return state, ok, nil
+}
+
+// Save implements Checkpoint
+func (f *FileCheckpoint) Save(key string, state SyncState) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states, err := f.read()
+	if err != nil {
+		return err
+	}
+	states[key] = state
+	content, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding checkpoint file")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "writing checkpoint file")
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	return errors.Wrap(err, "writing checkpoint file")
+}
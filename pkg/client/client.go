@@ -9,15 +9,19 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/TokenTax/etherscan-api/v2/pkg/chain"
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
 	"github.com/pkg/errors"
 )
 
@@ -30,7 +34,8 @@ type (
 		baseURL string
 		chain   chain.Chain
 
-		// Verbose when true, talks a lot
+		// Verbose when true, talks a lot: every request is logged at debug level,
+		// with its response body, to Logger or else to stderr
 		Verbose bool
 
 		// BeforeRequest runs before every client request, in the same goroutine.
@@ -40,6 +45,21 @@ type (
 
 		// AfterRequest runs after every client request, even when there is an error.
 		AfterRequest func(module, action string, values url.Values, outcome interface{}, requestErr error) error
+
+		interceptors    []Interceptor
+		keyPool         *KeyPool
+		rateLimit       Plan
+		rateLimitPolicy LimitPolicy
+		retryPolicy     RetryPolicy
+		cache           Cache
+		cachePolicy     CachePolicy
+		flights         *flightGroup
+		logger          *slog.Logger
+
+		heads     flightGroup
+		headMu    sync.Mutex
+		headBlock int
+		headAt    time.Time
 	}
 
 	// Customization is used in NewCustomized()
@@ -52,6 +72,9 @@ type (
 		BaseURL string
 		// When true, talks a lot
 		Verbose bool
+		// Logger receives a record for every request and retry,
+		// API keys are redacted. Nil means no logging unless Verbose is set.
+		Logger *slog.Logger
 		// ChainID to be used
 		Chain chain.Chain
 		// HTTP Client to be used. Specifying this value will ignore the Timeout value set
@@ -65,6 +88,37 @@ type (
 
 		// AfterRequest runs after every client request, even when there is an error.
 		AfterRequest func(module, action string, values url.Values, outcome interface{}, requestErr error) error
+
+		// Interceptors wrap every client request, see Client.Use.
+		Interceptors []Interceptor
+
+		// KeyPool spreads requests over several API keys, Key is ignored when set.
+		KeyPool *KeyPool
+
+		// RateLimit enables the built-in rate limiter, e.g. PlanFree.
+		// The limiter is shared by all clients using the same key,
+		// each key of a KeyPool gets its own. Clients sharing a key must agree on the plan,
+		// requests fail with ErrPlanConflict otherwise.
+		// Zero value means no client-side limit.
+		RateLimit Plan
+		// RateLimitPolicy decides whether this client blocks or fails fast once RateLimit is reached,
+		// defaults to LimitBlock.
+		RateLimitPolicy LimitPolicy
+
+		// Retry failed requests according to this policy, e.g. DefaultRetryPolicy.
+		// Zero value means no retries.
+		Retry RetryPolicy
+
+		// Cache successful responses, e.g. NewMemoryCache() or NewFileCache(dir).
+		// Nil means no caching.
+		Cache Cache
+		// CachePolicy decides how long responses stay in Cache, zero value means DefaultCachePolicy.
+		CachePolicy CachePolicy
+
+		// CoalesceRequests makes identical concurrent requests (same chain, module, action and values)
+		// share a single HTTP call. All callers then receive the same decoded result,
+		// so slices must be treated as read-only.
+		CoalesceRequests bool
 	}
 )
 
@@ -88,165 +142,257 @@ func NewCustomized(config Customization) *Client {
 	} else {
 		httpClient = &http.Client{Timeout: config.Timeout}
 	}
+	var flights *flightGroup
+	if config.CoalesceRequests {
+		flights = newFlightGroup()
+	}
+	cachePolicy := config.CachePolicy
+	if cachePolicy == (CachePolicy{}) {
+		cachePolicy = DefaultCachePolicy
+	}
 	return &Client{
-		conn:          httpClient,
-		key:           config.Key,
-		chain:         config.Chain,
-		baseURL:       config.BaseURL,
-		Verbose:       config.Verbose,
-		BeforeRequest: config.BeforeRequest,
-		AfterRequest:  config.AfterRequest,
+		conn:            httpClient,
+		key:             config.Key,
+		chain:           config.Chain,
+		baseURL:         config.BaseURL,
+		Verbose:         config.Verbose,
+		BeforeRequest:   config.BeforeRequest,
+		AfterRequest:    config.AfterRequest,
+		interceptors:    append([]Interceptor(nil), config.Interceptors...),
+		keyPool:         config.KeyPool,
+		rateLimit:       config.RateLimit,
+		rateLimitPolicy: config.RateLimitPolicy,
+		retryPolicy:     config.Retry,
+		cache:           config.Cache,
+		cachePolicy:     cachePolicy,
+		flights:         flights,
+		logger:          config.Logger,
 	}
 }
 
-func (c *Client) execute(module, action string, values url.Values) (bytes.Buffer, error) {
+// execute sends a single GET request for module and action, bound to ctx,
+// and returns the raw response body.
+func (c *Client) execute(ctx context.Context, module, action string, values url.Values) (bytes.Buffer, error) {
 	var content = bytes.Buffer{}
 
-	req, err := http.NewRequest(http.MethodGet, c.craftURL(module, action, values), http.NoBody)
+	res, err := c.send(ctx, module, action, values)
 	if err != nil {
-		return content, errors.Wrap(err, "creating request")
-	}
-	req.Header.Set("User-Agent", "etherscan-api(Go)")
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-
-	if c.Verbose {
-		reqDump, err := httputil.DumpRequestOut(req, false)
-		if err != nil {
-			return content, errors.Wrap(err, "verbose mode: dumping request")
+		var apiErr *response.Error
+		if errors.As(err, &apiErr) {
+			content.WriteString(apiErr.Result)
 		}
-
-		fmt.Printf("\n%s\n", reqDump)
-
-		defer func() {
-			if err != nil {
-				fmt.Printf("[Error] %v\n", err)
-			}
-		}()
-	}
-
-	res, err := c.conn.Do(req)
-	if err != nil {
-		return content, errors.Wrap(err, "sending request")
+		return content, err
 	}
 	defer res.Body.Close()
 
-	if c.Verbose {
-		resDump, err := httputil.DumpResponse(res, true)
-		if err != nil {
-			return content, errors.Wrap(err, "verbose mode:dumping response")
-		}
-
-		fmt.Printf("%s\n", resDump)
-	}
-
 	if _, err = io.Copy(&content, res.Body); err != nil {
 		return content, errors.Wrap(err, "reading response")
 	}
-
-	if res.StatusCode != http.StatusOK {
-		return content, errors.Errorf("got non-200 status code; status: %v, status text: %s, response body: %s", res.StatusCode, res.Status, content.String())
-	}
-
 	return content, nil
 }
 
-/*
-func (c *Client) innerCall(module, action string, values url.Values, outcome any) error {
-	req, err := http.NewRequest(http.MethodGet, c.craftURL(module, action, values), http.NoBody)
+// send issues the HTTP request and returns the response of status 200,
+// whose body the caller must close.
+func (c *Client) send(ctx context.Context, module, action string, values url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.craftURL(module, action, values), http.NoBody)
 	if err != nil {
-		return errors.Wrap(err, "creating request")
+		return nil, errors.Wrap(err, "creating request")
 	}
 	req.Header.Set("User-Agent", "etherscan-api(Go)")
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	if c.Verbose {
-		reqDump, err := httputil.DumpRequestOut(req, false)
+	res, err := c.conn.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		return nil, errors.Wrap(err, "sending request")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
 		if err != nil {
-			return errors.Wrap(err, "verbose mode: dumping request")
+			return nil, errors.Wrap(err, "reading response")
 		}
+		return nil, response.NewHTTPError(res.StatusCode, res.Status, string(body), parseRetryAfter(res.Header.Get("Retry-After"), time.Now()))
+	}
+	return res, nil
+}
+
+// listActions answer an empty list with status 0 and "No transactions found",
+// "No records found" or "No data found", call turns that into an empty result with no error.
+var listActions = map[string]bool{
+	"account/txlist":                   true,
+	"account/txlistinternal":           true,
+	"account/tokentx":                  true,
+	"account/tokennfttx":               true,
+	"account/token1155tx":              true,
+	"account/getminedblocks":           true,
+	"account/txsBeaconWithdrawal":      true,
+	"account/addresstokenbalance":      true,
+	"account/addresstokennftbalance":   true,
+	"account/addresstokennftinventory": true,
+	"logs/getLogs":                     true,
+}
+
+// emptyList returns an empty, non-nil slice when T is a slice type
+func emptyList[T response.EtherscanResponse]() T {
+	var ret T
+	_ = json.Unmarshal([]byte("[]"), &ret)
+	return ret
+}
 
-		fmt.Printf("\n%s\n", reqDump)
+// call runs an API request through the interceptor chain,
+// retrying it as configured, and decodes its result into T.
+// Identical concurrent calls share a single request when coalescing is on.
+func call[T response.EtherscanResponse](ctx context.Context, c *Client, module, action string, values url.Values) (T, error) {
+	var ret T
 
-		defer func() {
-			if err != nil {
-				fmt.Printf("[Error] %v\n", err)
-			}
-		}()
+	handler := c.handler(func(ctx context.Context, req *Request) (interface{}, error) {
+		return roundTrip[T](ctx, c, req)
+	})
+
+	run := func(ctx context.Context) (interface{}, error) {
+		return c.retry(ctx, handler, module, action, values)
 	}
 
-	res, err := c.conn.Do(req)
+	var outcome interface{}
+	var err error
+	if c.flights != nil {
+		outcome, err = c.flights.do(ctx, c.cacheKey(module, action, values), run)
+	} else {
+		outcome, err = run(ctx)
+	}
 	if err != nil {
-		return errors.Wrap(err, "sending request")
+		return ret, err
+	}
+	if outcome == nil {
+		return ret, nil
 	}
-	defer res.Body.Close()
-
-	if c.Verbose {
-		resDump, err := httputil.DumpResponse(res, true)
-		if err != nil {
-			return errors.Wrap(err, "verbose mode:dumping response")
-		}
 
-		fmt.Printf("%s\n", resDump)
+	ret, ok := outcome.(T)
+	if !ok {
+		return ret, errors.Errorf("interceptor returned outcome of type %T, want %T", outcome, ret)
 	}
+	return ret, nil
+}
 
-	resp := response.ReadResponse(res)
+// roundTrip answers req from the cache if possible, otherwise picks a key,
+// waits for the rate limiter, sends req and caches the response.
+func roundTrip[T response.EtherscanResponse](ctx context.Context, c *Client, req *Request) (T, error) {
+	var cacheKey string
+	if c.cacheable(ctx) {
+		cacheKey = c.cacheKey(req.Module, req.Action, req.Values)
+		if value, ok := c.cache.Get(cacheKey); ok {
+			ret, err := decode[T](req, *bytes.NewBuffer(value))
+			c.logRequest(ctx, req, time.Now(), value, true, err)
+			return ret, err
+		}
+	}
 
-	if res.StatusCode != http.StatusOK {
-		return errors.Errorf("got non-200 status code; status: %v, status text: %s, response body: %s", res.StatusCode, res.Status, content.String())
+	values, key, err := c.acquire(ctx, req.Values)
+	if err != nil {
+		var ret T
+		return ret, err
 	}
 
-	var content bytes.Buffer
-	if _, err = io.Copy(&content, res.Body); err != nil {
-		return errors.Wrap(err, "reading response")
+	start := time.Now()
+	body, err := c.execute(ctx, req.Module, req.Action, values)
+	if err != nil {
+		var apiErr *response.Error
+		if errors.As(err, &apiErr) {
+			apiErr.Module, apiErr.Action = req.Module, req.Action
+		}
+		if c.keyPool != nil {
+			c.keyPool.report(key, err)
+		}
+		c.logRequest(ctx, req, start, body.Bytes(), false, err)
+		var ret T
+		return ret, err
 	}
 
-	var envelope Envelope
-	if err = json.Unmarshal(content.Bytes(), &envelope); err != nil {
-		return errors.Wrap(err, "unmarshaling response")
+	ret, err := decode[T](req, body)
+	if c.keyPool != nil {
+		c.keyPool.report(key, err)
 	}
-	if envelope.Status != 1 {
-		return errors.Errorf("etherscan server: %s", envelope.Message)
+	c.logRequest(ctx, req, start, body.Bytes(), false, err)
+	if err == nil && cacheKey != "" {
+		if ttl, ok := c.cacheTTL(ctx, req.Module, req.Action, req.Values); ok {
+			c.cache.Set(cacheKey, body.Bytes(), ttl)
+		}
 	}
+	return ret, err
+}
 
-	// workaround for missing tokenDecimal for some tokentx calls
-	if action == "tokentx" {
-		err = json.Unmarshal(bytes.Replace(envelope.Result, []byte(`"tokenDecimal":""`), []byte(`"tokenDecimal":"0"`), -1), outcome)
-	} else {
-		err = json.Unmarshal(envelope.Result, outcome)
+// acquire picks the key to send values with and waits for its rate limiter,
+// returning values carrying the key.
+func (c *Client) acquire(ctx context.Context, values url.Values) (url.Values, string, error) {
+	key := c.key
+	if c.keyPool != nil {
+		var err error
+		if key, err = c.keyPool.pick(); err != nil {
+			return nil, "", err
+		}
+		values = cloneValues(values)
+		values.Set("apikey", key)
 	}
 
-	return errors.Wrap(err, "unmarshaling result")
-}
-
-// call executes the Before/AfterRequest hooks and executes innerCall in between
-func (c *Client) call(module, action string, values url.Values, outcome interface{}) error {
-	// fire hooks if in need
-	if c.BeforeRequest != nil {
-		if err := c.BeforeRequest(module, action, values); err != nil {
-			return errors.Wrap(err, "running beforeRequest")
+	limiter, err := c.limiterFor(key)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "sharing rate limiter")
+	}
+	if limiter != nil {
+		if err := limiter.WaitPolicy(ctx, c.rateLimitPolicy); err != nil {
+			return nil, "", errors.Wrap(err, "waiting for rate limiter")
 		}
 	}
+	return values, key, nil
+}
 
-	err := c.innerCall(module, action, values, outcome)
-	if c.AfterRequest != nil {
-		if err := c.AfterRequest(module, action, param, outcome, err); err != nil {
-			return errors.Wrapf(err, "running afterRequest with request Error: %v", err)
+// decode reads body as T, turning "No transactions found" of list actions into an empty list
+func decode[T response.EtherscanResponse](req *Request, body bytes.Buffer) (T, error) {
+	ret, err := response.ReadResponse[T](body)
+
+	var apiErr *response.Error
+	if errors.As(err, &apiErr) {
+		if errors.Is(apiErr, response.ErrNotFound) && listActions[req.Module+"/"+req.Action] {
+			return emptyList[T](), nil
 		}
+		apiErr.Module, apiErr.Action, apiErr.HTTPStatus = req.Module, req.Action, http.StatusOK
 	}
-	return err
+	return ret, err
 }
-*/
 
-// craftURL returns desired URL via param provided
+// craftURL returns desired URL via param provided,
+// values is left untouched. An apikey already in values wins over the client's key.
 func (c *Client) craftURL(module, action string, values url.Values) string {
-	if values == nil {
-		values = url.Values{}
-	}
+	values = cloneValues(values)
 
 	values.Add("module", module)
 	values.Add("action", action)
-	values.Add("apikey", c.key)
+	if values.Get("apikey") == "" {
+		values.Set("apikey", c.key)
+	}
 	values.Add("chainid", strconv.Itoa(c.chain.ID()))
 
 	return fmt.Sprintf("%s?%s", c.baseURL, values.Encode())
 }
+
+// cloneValues returns a deep copy of values, never nil
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for k, v := range values {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
+
+// limiterFor returns the shared rate limiter of key, nil when rate limiting is off
+func (c *Client) limiterFor(key string) (*RateLimiter, error) {
+	if c.rateLimit == (Plan{}) {
+		return nil, nil
+	}
+	return SharedRateLimiter(key, c.rateLimit)
+}
@@ -8,10 +8,16 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/TokenTax/etherscan-api/v2/pkg/chain"
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,3 +35,72 @@ func TestClient_craftURL(t *testing.T) {
 
 	assert.Equal(t, expected, output)
 }
+
+func TestClient_ContextCancellation(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.AccountBalanceContext(ctx, "0x0000000000000000000000000000000000000000")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// newTestClient returns a client talking to a local server driven by handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return NewCustomized(Customization{
+		Key:     "abc123",
+		Chain:   chain.EthereumMainnet,
+		BaseURL: srv.URL,
+	})
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+	})
+
+	_, err := c.ContractSource("0x0000000000000000000000000000000000000000")
+	assert.ErrorIs(t, err, response.ErrInvalidAPIKey)
+
+	var apiErr *response.Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, "contract", apiErr.Module)
+		assert.Equal(t, "getsourcecode", apiErr.Action)
+		assert.Equal(t, http.StatusOK, apiErr.HTTPStatus)
+		assert.Equal(t, "NOTOK", apiErr.Message)
+		assert.Equal(t, "Invalid API Key", apiErr.Result)
+	}
+}
+
+func TestClient_NoRecordsFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "txlist":
+			fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+		case "getLogs":
+			fmt.Fprint(w, `{"status":"0","message":"No records found","result":[]}`)
+		default:
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`)
+		}
+	})
+
+	txs, err := c.NormalTxByAddress("0x0000000000000000000000000000000000000000", nil, nil, 1, 10, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, txs)
+	assert.Empty(t, txs)
+
+	logs, err := c.GetLogs(1, 2, "0x0000000000000000000000000000000000000000", "")
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+
+	_, err = c.ContractABI("0x0000000000000000000000000000000000000000")
+	assert.ErrorIs(t, err, response.ErrNotFound)
+}
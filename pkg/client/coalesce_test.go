@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters blocks until n callers wait on a single flight of g
+func waitForWaiters(t *testing.T, g *flightGroup, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		for _, f := range g.flights {
+			if f.waiters == n {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+}
+
+func TestClient_CoalesceRequests(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"[abi]"}`)
+	})
+	c.flights = newFlightGroup()
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			abi, err := c.ContractABI("0x0000000000000000000000000000000000000000")
+			assert.NoError(t, err)
+			results[i] = abi
+		}(i)
+	}
+
+	waitForWaiters(t, c.flights, callers)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	for _, abi := range results {
+		assert.Equal(t, "[abi]", abi)
+	}
+}
+
+func TestFlightGroup_WaiterCancellation(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, "key", fn)
+		first <- err
+	}()
+	waitForWaiters(t, g, 1)
+
+	second := make(chan interface{}, 1)
+	go func() {
+		outcome, _ := g.do(context.Background(), "key", fn)
+		second <- outcome
+	}()
+	waitForWaiters(t, g, 2)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "done", <-second)
+}
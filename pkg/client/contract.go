@@ -8,6 +8,7 @@
 package client
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/TokenTax/etherscan-api/v2/pkg/response"
@@ -28,26 +29,36 @@ func (p ContractParams) GetUrlValues() url.Values {
 
 // ContractABI gets contract abi for verified contract source codes
 func (c *Client) ContractABI(address string) (string, error) {
+	return c.ContractABIContext(context.Background(), address)
+}
+
+// ContractABIContext is like ContractABI but bound to ctx
+func (c *Client) ContractABIContext(ctx context.Context, address string) (string, error) {
 	param := ContractParams{
 		Address: address,
 	}
 
-	body, err := c.execute("contract", "getabi", param.GetUrlValues())
+	result, err := call[string](ctx, c, "contract", "getabi", param.GetUrlValues())
 	if err != nil {
 		return "", errors.Wrap(err, "executing ContractABI request")
 	}
-	return response.ReadResponse[string](body)
+	return result, nil
 }
 
 // ContractSource gets contract source code for verified contract source codes
 func (c *Client) ContractSource(address string) ([]response.ContractSource, error) {
+	return c.ContractSourceContext(context.Background(), address)
+}
+
+// ContractSourceContext is like ContractSource but bound to ctx
+func (c *Client) ContractSourceContext(ctx context.Context, address string) ([]response.ContractSource, error) {
 	param := ContractParams{
 		Address: address,
 	}
 
-	body, err := c.execute("contract", "getsourcecode", param.GetUrlValues())
+	result, err := call[[]response.ContractSource](ctx, c, "contract", "getsourcecode", param.GetUrlValues())
 	if err != nil {
 		return nil, errors.Wrap(err, "executing ContractSource request")
 	}
-	return response.ReadResponse[[]response.ContractSource](body)
+	return result, nil
 }
@@ -8,6 +8,7 @@
 package client
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 	"time"
@@ -28,28 +29,33 @@ func (p GasEstimateParams) GetUrlValues() url.Values {
 
 // GasEstimate gets estimated confirmation time (in seconds) at the given gas price
 func (c *Client) GasEstimate(gasPrice int) (time.Duration, error) {
+	return c.GasEstimateContext(context.Background(), gasPrice)
+}
+
+// GasEstimateContext is like GasEstimate but bound to ctx
+func (c *Client) GasEstimateContext(ctx context.Context, gasPrice int) (time.Duration, error) {
 	param := GasEstimateParams{
 		GasPrice: gasPrice,
 	}
 
-	body, err := c.execute("gastracker", "gasestimate", param.GetUrlValues())
+	confTime, err := call[string](ctx, c, "gastracker", "gasestimate", param.GetUrlValues())
 	if err != nil {
 		return 0, errors.Wrap(err, "executing GasEstimate request")
 	}
 
-	confTime, err := response.ReadResponse[string](body)
-	if err != nil {
-		return 0, errors.Wrap(err, "reading response")
-	}
-
 	return time.ParseDuration(confTime + "s")
 }
 
 // GasOracle gets suggested gas prices (in Gwei)
 func (c *Client) GasOracle() (response.GasPrices, error) {
-	body, err := c.execute("gastracker", "gasoracle", url.Values{})
+	return c.GasOracleContext(context.Background())
+}
+
+// GasOracleContext is like GasOracle but bound to ctx
+func (c *Client) GasOracleContext(ctx context.Context) (response.GasPrices, error) {
+	result, err := call[response.GasPrices](ctx, c, "gastracker", "gasoracle", url.Values{})
 	if err != nil {
 		return response.GasPrices{}, errors.Wrap(err, "executing GasOracle request")
 	}
-	return response.ReadResponse[response.GasPrices](body)
+	return result, nil
 }
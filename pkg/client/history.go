@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// MaxResultWindow is the largest page*offset etherscan serves for a single query,
+// beyond it the API answers "Result window is too large".
+const MaxResultWindow = 10000
+
+// HistoryFetcher downloads complete account histories, however long,
+// by slicing the block range into windows that each fit in MaxResultWindow.
+//
+// A window that fills up is cut at its last block, which is fetched again
+// as part of the next, narrower window. Sparse windows make the next one wider.
+type HistoryFetcher struct {
+	client *Client
+
+	// PageSize per request, at most MaxResultWindow, defaults to DefaultPageSize
+	PageSize int
+	// InitialWindow is the width in blocks of the first window, the whole range when zero
+	InitialWindow int
+}
+
+// NewHistoryFetcher creates a HistoryFetcher using c
+func NewHistoryFetcher(c *Client) *HistoryFetcher {
+	return &HistoryFetcher{client: c}
+}
+
+// windowFetch fetches one page of records between startBlock and endBlock inclusive, in ascending order
+type windowFetch[T any] func(ctx context.Context, startBlock, endBlock, page, offset int) ([]T, error)
+
+// fetchHistory walks startBlock to endBlock (pinned to the latest block when nil)
+// window by window and returns every record in ascending block order.
+func fetchHistory[T interface{ GetBlockNumber() int }](ctx context.Context, h *HistoryFetcher, startBlock int, endBlock *int, fetch windowFetch[T]) ([]T, error) {
+	end := 0
+	if endBlock != nil {
+		end = *endBlock
+	} else {
+		latest, err := h.client.latestBlock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		end = latest
+	}
+
+	pageSize := h.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxResultWindow {
+		pageSize = MaxResultWindow
+	}
+	width := h.InitialWindow
+	if width <= 0 {
+		width = end - startBlock + 1
+	}
+
+	var history []T
+
+	for lo := startBlock; lo <= end; {
+		hi := end
+		if width < end-lo+1 {
+			hi = lo + width - 1
+		}
+
+		records, full, err := fetchWindow(ctx, lo, hi, pageSize, fetch)
+		if err != nil {
+			return history, errors.Wrapf(err, "fetching blocks %d to %d", lo, hi)
+		}
+
+		next := hi + 1
+		if full {
+			last := records[len(records)-1].GetBlockNumber()
+			if last <= lo {
+				return history, errors.Wrapf(response.ErrResultWindowExceeded, "block %d alone holds more than %d records", lo, MaxResultWindow)
+			}
+			// the last block may be cut short, fetch it again with the next window
+			cut := len(records)
+			for cut > 0 && records[cut-1].GetBlockNumber() == last {
+				cut--
+			}
+			records, next = records[:cut], last
+			width = max((hi-lo+1)/2, 1)
+		} else if len(records) < MaxResultWindow/4 {
+			width = min(width*2, end-startBlock+1)
+		}
+
+		history = append(history, records...)
+		lo = next
+	}
+
+	return history, nil
+}
+
+// fetchWindow pages through a single window, reporting whether it hit MaxResultWindow
+func fetchWindow[T any](ctx context.Context, lo, hi, pageSize int, fetch windowFetch[T]) ([]T, bool, error) {
+	var records []T
+	for page := 1; page*pageSize <= MaxResultWindow; page++ {
+		items, err := fetch(ctx, lo, hi, page, pageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		records = append(records, items...)
+		if len(items) < pageSize {
+			return records, false, nil
+		}
+	}
+	return records, true, nil
+}
+
+// NormalTxs returns every "normal" tx of address from startBlock to endBlock,
+// endBlock defaults to the latest block when nil.
+func (h *HistoryFetcher) NormalTxs(ctx context.Context, address string, startBlock int, endBlock *int) ([]response.NormalTx, error) {
+	return fetchHistory(ctx, h, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.NormalTx, error) {
+		return h.client.NormalTxByAddressContext(ctx, address, &start, &end, page, offset, false)
+	})
+}
+
+// InternalTxs returns every "internal" tx of address, see NormalTxs
+func (h *HistoryFetcher) InternalTxs(ctx context.Context, address string, startBlock int, endBlock *int) ([]response.InternalTx, error) {
+	return fetchHistory(ctx, h, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.InternalTx, error) {
+		return h.client.InternalTxByAddressContext(ctx, address, &start, &end, page, offset, false)
+	})
+}
+
+// ERC20Transfers returns every erc20 transfer event matching contractAddress and/or address,
+// see NormalTxs
+func (h *HistoryFetcher) ERC20Transfers(ctx context.Context, contractAddress, address *string, startBlock int, endBlock *int) ([]response.ERC20Transfer, error) {
+	return fetchHistory(ctx, h, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.ERC20Transfer, error) {
+		return h.client.ERC20TransfersContext(ctx, contractAddress, address, &start, &end, page, offset, false)
+	})
+}
+
+// ERC721Transfers returns every erc721 transfer event matching contractAddress and/or address,
+// see NormalTxs
+func (h *HistoryFetcher) ERC721Transfers(ctx context.Context, contractAddress, address *string, startBlock int, endBlock *int) ([]response.ERC721Transfer, error) {
+	return fetchHistory(ctx, h, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.ERC721Transfer, error) {
+		return h.client.ERC721TransfersContext(ctx, contractAddress, address, &start, &end, page, offset, false)
+	})
+}
+
+// ERC1155Transfers returns every erc1155 transfer event matching contractAddress and/or address,
+// see NormalTxs
+func (h *HistoryFetcher) ERC1155Transfers(ctx context.Context, contractAddress, address *string, startBlock int, endBlock *int) ([]response.ERC1155Transfer, error) {
+	return fetchHistory(ctx, h, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.ERC1155Transfer, error) {
+		return h.client.ERC1155TransfersContext(ctx, contractAddress, address, &start, &end, page, offset, false)
+	})
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryFetcher_NormalTxs(t *testing.T) {
+	const blocks, perBlock = 1000, 25
+
+	var requests int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		start, _ := strconv.Atoi(q.Get("startblock"))
+		end, _ := strconv.Atoi(q.Get("endblock"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		if page*offset > MaxResultWindow {
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Result window is too large, PageNo x Offset size must be less than or equal to 10000"}`)
+			return
+		}
+
+		var txs []map[string]string
+		for block := max(start, 0); block <= min(end, blocks-1); block++ {
+			for i := 0; i < perBlock; i++ {
+				txs = append(txs, map[string]string{
+					"blockNumber":      strconv.Itoa(block),
+					"transactionIndex": strconv.Itoa(i),
+					"timeStamp":        "0",
+					"hash":             fmt.Sprintf("0x%d_%d", block, i),
+				})
+			}
+		}
+		from := min((page-1)*offset, len(txs))
+		to := min(page*offset, len(txs))
+		result, _ := json.Marshal(txs[from:to])
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, result)
+	})
+
+	h := NewHistoryFetcher(c)
+	h.PageSize = 5000
+	end := blocks - 1
+	txs, err := h.NormalTxs(context.Background(), "0x1", 0, &end)
+	assert.NoError(t, err)
+	assert.Len(t, txs, blocks*perBlock)
+
+	seen := map[string]bool{}
+	for i, tx := range txs {
+		assert.False(t, seen[tx.Hash], "duplicate %s", tx.Hash)
+		seen[tx.Hash] = true
+		if i > 0 {
+			prev := txs[i-1]
+			assert.True(t, prev.BlockNumber < tx.BlockNumber || prev.TransactionIndex < tx.TransactionIndex, "out of order at %d", i)
+		}
+	}
+	assert.Less(t, requests, 20)
+}
+
+func TestHistoryFetcher_BlockOverWindow(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		start, _ := strconv.Atoi(q.Get("startblock"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+
+		// block 0 holds 3 txs, block 1 more than the result window
+		var txs []map[string]string
+		if start == 0 {
+			for i := 0; i < 3; i++ {
+				txs = append(txs, map[string]string{"blockNumber": "0", "timeStamp": "0", "hash": fmt.Sprintf("0x0_%d", i)})
+			}
+		}
+		for i := 0; i < MaxResultWindow+1; i++ {
+			txs = append(txs, map[string]string{"blockNumber": "1", "timeStamp": "0", "hash": fmt.Sprintf("0x1_%d", i)})
+		}
+		from := min((page-1)*offset, len(txs))
+		to := min(page*offset, len(txs))
+		result, _ := json.Marshal(txs[from:to])
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, result)
+	})
+
+	h := NewHistoryFetcher(c)
+	h.PageSize = 5000
+	end := 1
+	txs, err := h.NormalTxs(context.Background(), "0x1", 0, &end)
+	assert.ErrorIs(t, err, response.ErrResultWindowExceeded)
+	assert.ErrorContains(t, err, "block 1 alone")
+	assert.Len(t, txs, 3)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+type AddressTokenParams struct {
+	Address string `json:"address"`
+	// ContractAddress narrows addresstokennftinventory down to a single collection
+	ContractAddress string `json:"contractaddress,omitempty"`
+	// Page and Offset are only sent when positive
+	Page   int `json:"page,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+func (p AddressTokenParams) GetUrlValues() url.Values {
+	values := url.Values{}
+	if p.Address != "" {
+		values.Add("address", p.Address)
+	}
+	if p.ContractAddress != "" {
+		values.Add("contractaddress", p.ContractAddress)
+	}
+	if p.Page > 0 {
+		values.Add("page", strconv.Itoa(p.Page))
+	}
+	if p.Offset > 0 {
+		values.Add("offset", strconv.Itoa(p.Offset))
+	}
+	return values
+}
+
+// AddressTokenBalances gets the erc20 tokens held by address with their quantities,
+// the given page of offset tokens
+func (c *Client) AddressTokenBalances(address string, page int, offset int) ([]response.TokenHolding, error) {
+	return c.AddressTokenBalancesContext(context.Background(), address, page, offset)
+}
+
+// AddressTokenBalancesContext is like AddressTokenBalances but bound to ctx
+func (c *Client) AddressTokenBalancesContext(ctx context.Context, address string, page int, offset int) ([]response.TokenHolding, error) {
+	param := AddressTokenParams{
+		Address: address,
+		Page:    page,
+		Offset:  offset,
+	}
+	result, err := call[[]response.TokenHolding](ctx, c, "account", "addresstokenbalance", param.GetUrlValues())
+	if err != nil {
+		return []response.TokenHolding{}, errors.Wrap(err, "executing AddressTokenBalances request")
+	}
+	return result, nil
+}
+
+// AddressNFTBalances gets the erc721 collections address holds tokens of,
+// with how many of them, the given page of offset collections
+func (c *Client) AddressNFTBalances(address string, page int, offset int) ([]response.NFTHolding, error) {
+	return c.AddressNFTBalancesContext(context.Background(), address, page, offset)
+}
+
+// AddressNFTBalancesContext is like AddressNFTBalances but bound to ctx
+func (c *Client) AddressNFTBalancesContext(ctx context.Context, address string, page int, offset int) ([]response.NFTHolding, error) {
+	param := AddressTokenParams{
+		Address: address,
+		Page:    page,
+		Offset:  offset,
+	}
+	result, err := call[[]response.NFTHolding](ctx, c, "account", "addresstokennftbalance", param.GetUrlValues())
+	if err != nil {
+		return []response.NFTHolding{}, errors.Wrap(err, "executing AddressNFTBalances request")
+	}
+	return result, nil
+}
+
+// AddressNFTInventory gets the erc721 token ids address holds in contractAddress,
+// the given page of offset tokens
+func (c *Client) AddressNFTInventory(address, contractAddress string, page int, offset int) ([]response.NFTInventoryItem, error) {
+	return c.AddressNFTInventoryContext(context.Background(), address, contractAddress, page, offset)
+}
+
+// AddressNFTInventoryContext is like AddressNFTInventory but bound to ctx
+func (c *Client) AddressNFTInventoryContext(ctx context.Context, address, contractAddress string, page int, offset int) ([]response.NFTInventoryItem, error) {
+	param := AddressTokenParams{
+		Address:         address,
+		ContractAddress: contractAddress,
+		Page:            page,
+		Offset:          offset,
+	}
+	result, err := call[[]response.NFTInventoryItem](ctx, c, "account", "addresstokennftinventory", param.GetUrlValues())
+	if err != nil {
+		return []response.NFTInventoryItem{}, errors.Wrap(err, "executing AddressNFTInventory request")
+	}
+	return result, nil
+}
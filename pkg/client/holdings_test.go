@@ -0,0 +1,89 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressTokenParams_GetUrlValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   AddressTokenParams
+		expected url.Values
+	}{
+		{
+			name:     "empty params",
+			params:   AddressTokenParams{},
+			expected: url.Values{},
+		},
+		{
+			name: "full params",
+			params: AddressTokenParams{
+				Address:         "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+				ContractAddress: "0x742d35Cc6634C0532925a3b844Bc454e4438f44f",
+				Page:            2,
+				Offset:          100,
+			},
+			expected: url.Values{
+				"address":         []string{"0x742d35Cc6634C0532925a3b844Bc454e4438f44e"},
+				"contractaddress": []string{"0x742d35Cc6634C0532925a3b844Bc454e4438f44f"},
+				"page":            []string{"2"},
+				"offset":          []string{"100"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.params.GetUrlValues()
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestClient_AddressTokenBalances(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "addresstokenbalance", q.Get("action"))
+		assert.Equal(t, "0x1", q.Get("address"))
+		assert.Equal(t, "1", q.Get("page"))
+		assert.Equal(t, "100", q.Get("offset"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"TokenAddress":"0xc","TokenName":"Tether USD","TokenSymbol":"USDT","TokenQuantity":"1500000","TokenDivisor":"6"}]}`)
+	})
+	holdings, err := c.AddressTokenBalances("0x1", 1, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, holdings, 1) {
+		assert.Equal(t, "USDT", holdings[0].TokenSymbol)
+		assert.Equal(t, "1500000", holdings[0].TokenQuantity.Int().String())
+		assert.Equal(t, 6, holdings[0].TokenDecimal)
+	}
+}
+
+func TestClient_AddressNFTBalances(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "addresstokennftbalance", r.URL.Query().Get("action"))
+		fmt.Fprint(w, `{"status":"0","message":"No data found","result":[]}`)
+	})
+	holdings, err := c.AddressNFTBalances("0x1", 1, 100)
+	assert.NoError(t, err)
+	assert.NotNil(t, holdings)
+	assert.Empty(t, holdings)
+}
+
+func TestClient_AddressNFTInventory(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "addresstokennftinventory", q.Get("action"))
+		assert.Equal(t, "0xc", q.Get("contractaddress"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"TokenAddress":"0xc","TokenId":"36"},{"TokenAddress":"0xc","TokenId":"1024"}]}`)
+	})
+	items, err := c.AddressNFTInventory("0x1", "0xc", 1, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "1024", items[1].TokenID.Int().String())
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Request describes a single API call travelling through the interceptor chain.
+	// Interceptors may modify Values before passing the request on.
+	Request struct {
+		Module string
+		Action string
+		Values url.Values
+		// Attempt starts at 1 and grows with every retry
+		Attempt int
+	}
+
+	// Handler performs a Request and returns its decoded outcome,
+	// e.g. types.BigInt for account/balance or []response.NormalTx for account/txlist.
+	Handler func(ctx context.Context, req *Request) (outcome interface{}, err error)
+
+	// Interceptor wraps a request. It usually calls next exactly once,
+	// but may short-circuit by returning a synthetic outcome of the same type
+	// the Handler would have produced, or an error.
+	Interceptor func(ctx context.Context, req *Request, next Handler) (outcome interface{}, err error)
+)
+
+// Use appends interceptors to the chain.
+//
+// Interceptors run in the order they are registered: the first one is the outermost,
+// the last one sits right before BeforeRequest/AfterRequest and the HTTP round trip.
+// Use is not safe to call concurrently with requests.
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// HookInterceptor adapts a BeforeRequest/AfterRequest pair into an Interceptor.
+// Either hook may be nil.
+func HookInterceptor(
+	before func(module, action string, values url.Values) error,
+	after func(module, action string, values url.Values, outcome interface{}, requestErr error) error,
+) Interceptor {
+	return func(ctx context.Context, req *Request, next Handler) (interface{}, error) {
+		if before != nil {
+			if err := before(req.Module, req.Action, req.Values); err != nil {
+				return nil, errors.Wrap(err, "running beforeRequest")
+			}
+		}
+
+		outcome, err := next(ctx, req)
+		if after != nil {
+			if afterErr := after(req.Module, req.Action, req.Values, outcome, err); afterErr != nil {
+				return outcome, errors.Wrapf(afterErr, "running afterRequest with request Error: %v", err)
+			}
+		}
+		return outcome, err
+	}
+}
+
+// handler wraps final with the registered interceptors and the legacy hooks.
+//
+// The hook fields are read on every call, so assigning them after
+// the client is constructed keeps working.
+func (c *Client) handler(final Handler) Handler {
+	handler := final
+	if c.BeforeRequest != nil || c.AfterRequest != nil {
+		handler = wrap(HookInterceptor(c.BeforeRequest, c.AfterRequest), handler)
+	}
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		handler = wrap(c.interceptors[i], handler)
+	}
+	return handler
+}
+
+func wrap(interceptor Interceptor, next Handler) Handler {
+	return func(ctx context.Context, req *Request) (interface{}, error) {
+		return interceptor(ctx, req, next)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_InterceptorOrder(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("injected"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"42"}`)
+	})
+
+	var trace []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Handler) (interface{}, error) {
+			trace = append(trace, name+" before")
+			outcome, err := next(ctx, req)
+			trace = append(trace, name+" after")
+			return outcome, err
+		}
+	}
+	c.Use(record("first"), record("second"), func(ctx context.Context, req *Request, next Handler) (interface{}, error) {
+		req.Values.Set("injected", "1")
+		return next(ctx, req)
+	})
+	c.BeforeRequest = func(module, action string, values url.Values) error {
+		trace = append(trace, "BeforeRequest "+module+"/"+action)
+		return nil
+	}
+	c.AfterRequest = func(module, action string, values url.Values, outcome interface{}, requestErr error) error {
+		balance := outcome.(types.BigInt)
+		trace = append(trace, "AfterRequest "+balance.Int().String())
+		return requestErr
+	}
+
+	balance, err := c.AccountBalance("0x0000000000000000000000000000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", balance.Int().String())
+	assert.Equal(t, []string{
+		"first before",
+		"second before",
+		"BeforeRequest account/balance",
+		"AfterRequest 42",
+		"second after",
+		"first after",
+	}, trace)
+}
+
+func TestClient_InterceptorShortCircuit(t *testing.T) {
+	var hits int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	})
+	c.Use(func(ctx context.Context, req *Request, next Handler) (interface{}, error) {
+		return "0xabi", nil
+	})
+
+	abi, err := c.ContractABI("0x0000000000000000000000000000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, "0xabi", abi)
+	assert.Zero(t, atomic.LoadInt32(&hits))
+
+	_, err = c.GasOracle()
+	assert.ErrorContains(t, err, "interceptor returned outcome of type string")
+}
+
+func TestClient_BeforeRequestAborts(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("request should have been aborted")
+	})
+	c.BeforeRequest = func(module, action string, values url.Values) error {
+		return fmt.Errorf("slow down")
+	}
+
+	_, err := c.EtherTotalSupply()
+	assert.ErrorContains(t, err, "running beforeRequest: slow down")
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// DefaultPageSize is the page size iterators use when given a non-positive one
+const DefaultPageSize = 1000
+
+// Collect drains seq into a slice, stopping at the first error
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var all []T
+	for item, err := range seq {
+		if err != nil {
+			return all, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
+
+// paginate walks pages from 1 until a page comes back short,
+// yielding every item, or the error that stopped it.
+// A page reaching past MaxResultWindow is not requested, etherscan would reject it.
+func paginate[T any](pageSize int, fetch func(page, offset int) ([]T, error)) iter.Seq2[T, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		for page := 1; ; page++ {
+			if page*pageSize > MaxResultWindow {
+				var zero T
+				yield(zero, errors.Wrapf(response.ErrResultWindowExceeded,
+					"page %d of %d exceeds the first %d records, use HistoryFetcher to go past them", page, pageSize, MaxResultWindow))
+				return
+			}
+
+			items, err := fetch(page, pageSize)
+			if err != nil {
+				var zero T
+				yield(zero, errors.Wrapf(err, "fetching page %d", page))
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < pageSize {
+				return
+			}
+		}
+	}
+}
+
+// pinnedPages is paginate over a block range whose end, if open,
+// is pinned to the latest block when iteration starts, so blocks mined
+// meanwhile do not shift records between pages.
+func pinnedPages[T any](ctx context.Context, c *Client, endBlock *int, pageSize int, fetch func(endBlock *int, page, offset int) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		end := endBlock
+		if end == nil {
+			latest, err := c.latestBlock(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			end = &latest
+		}
+
+		paginate(pageSize, func(page, offset int) ([]T, error) {
+			return fetch(end, page, offset)
+		})(yield)
+	}
+}
+
+// latestBlock returns the latest block, used to pin open block ranges
+func (c *Client) latestBlock(ctx context.Context) (int, error) {
+	latest, err := c.BlockNumberContext(ctx, time.Now().Unix(), "before")
+	if err != nil {
+		return 0, errors.Wrap(err, "pinning end block")
+	}
+	return latest, nil
+}
+
+// NormalTxByAddressIter iterates over every "normal" tx of address, page by page.
+//
+// startBlock and endBlock can be nil, a nil endBlock is pinned to the latest block
+// when iteration starts. pageSize defaults to DefaultPageSize.
+//
+// Etherscan serves only the first MaxResultWindow records of a query,
+// past them the iterator yields response.ErrResultWindowExceeded.
+// Use HistoryFetcher for longer histories.
+func (c *Client) NormalTxByAddressIter(ctx context.Context, address string, startBlock, endBlock *int, pageSize int, desc bool) iter.Seq2[response.NormalTx, error] {
+	return pinnedPages(ctx, c, endBlock, pageSize, func(endBlock *int, page, offset int) ([]response.NormalTx, error) {
+		return c.NormalTxByAddressContext(ctx, address, startBlock, endBlock, page, offset, desc)
+	})
+}
+
+// InternalTxByAddressIter iterates over every "internal" tx of address, page by page.
+// See NormalTxByAddressIter for the parameters.
+func (c *Client) InternalTxByAddressIter(ctx context.Context, address string, startBlock, endBlock *int, pageSize int, desc bool) iter.Seq2[response.InternalTx, error] {
+	return pinnedPages(ctx, c, endBlock, pageSize, func(endBlock *int, page, offset int) ([]response.InternalTx, error) {
+		return c.InternalTxByAddressContext(ctx, address, startBlock, endBlock, page, offset, desc)
+	})
+}
+
+// ERC20TransfersIter iterates over every erc20 transfer event matching
+// contractAddress and/or address, page by page. See NormalTxByAddressIter for the rest.
+func (c *Client) ERC20TransfersIter(ctx context.Context, contractAddress, address *string, startBlock, endBlock *int, pageSize int, desc bool) iter.Seq2[response.ERC20Transfer, error] {
+	return pinnedPages(ctx, c, endBlock, pageSize, func(endBlock *int, page, offset int) ([]response.ERC20Transfer, error) {
+		return c.ERC20TransfersContext(ctx, contractAddress, address, startBlock, endBlock, page, offset, desc)
+	})
+}
+
+// ERC721TransfersIter iterates over every erc721 transfer event matching
+// contractAddress and/or address, page by page. See NormalTxByAddressIter for the rest.
+func (c *Client) ERC721TransfersIter(ctx context.Context, contractAddress, address *string, startBlock, endBlock *int, pageSize int, desc bool) iter.Seq2[response.ERC721Transfer, error] {
+	return pinnedPages(ctx, c, endBlock, pageSize, func(endBlock *int, page, offset int) ([]response.ERC721Transfer, error) {
+		return c.ERC721TransfersContext(ctx, contractAddress, address, startBlock, endBlock, page, offset, desc)
+	})
+}
+
+// ERC1155TransfersIter iterates over every erc1155 transfer event matching
+// contractAddress and/or address, page by page. See NormalTxByAddressIter for the rest.
+func (c *Client) ERC1155TransfersIter(ctx context.Context, contractAddress, address *string, startBlock, endBlock *int, pageSize int, desc bool) iter.Seq2[response.ERC1155Transfer, error] {
+	return pinnedPages(ctx, c, endBlock, pageSize, func(endBlock *int, page, offset int) ([]response.ERC1155Transfer, error) {
+		return c.ERC1155TransfersContext(ctx, contractAddress, address, startBlock, endBlock, page, offset, desc)
+	})
+}
+
+// BlocksMinedByAddressIter iterates over every block mined by address, page by page.
+// pageSize defaults to DefaultPageSize. Like NormalTxByAddressIter,
+// it yields response.ErrResultWindowExceeded past MaxResultWindow blocks.
+func (c *Client) BlocksMinedByAddressIter(ctx context.Context, address string, pageSize int) iter.Seq2[response.MinedBlock, error] {
+	return paginate(pageSize, func(page, offset int) ([]response.MinedBlock, error) {
+		return c.BlocksMinedByAddressContext(ctx, address, page, offset)
+	})
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+// txPage renders n normal transactions starting at block from as an etherscan response
+func txPage(from, n int) string {
+	txs := make([]string, n)
+	for i := range txs {
+		txs[i] = fmt.Sprintf(`{"blockNumber":"%d","timeStamp":"0","hash":"0x%x"}`, from+i, from+i)
+	}
+	return `{"status":"1","message":"OK","result":[` + strings.Join(txs, ",") + `]}`
+}
+
+func TestClient_NormalTxByAddressIter(t *testing.T) {
+	var endBlocks []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("action") == "getblocknobytime" {
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"500"}`)
+			return
+		}
+
+		endBlocks = append(endBlocks, q.Get("endblock"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		switch page {
+		case 1, 2:
+			fmt.Fprint(w, txPage(page*10, 2))
+		default:
+			fmt.Fprint(w, txPage(page*10, 1))
+		}
+	})
+
+	txs, err := Collect(c.NormalTxByAddressIter(context.Background(), "0x1", nil, nil, 2, false))
+	assert.NoError(t, err)
+	assert.Len(t, txs, 5)
+	assert.Equal(t, []string{"500", "500", "500"}, endBlocks)
+	assert.Equal(t, 30, txs[4].BlockNumber)
+}
+
+func TestClient_IterStopsOnError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Query Timeout occured"}`)
+			return
+		}
+		fmt.Fprint(w, txPage(1, 2))
+	})
+
+	end := 100
+	var count int
+	var lastErr error
+	for _, err := range c.InternalTxByAddressIter(context.Background(), "0x1", nil, &end, 2, false) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		count++
+	}
+	assert.Equal(t, 2, count)
+	assert.ErrorContains(t, lastErr, "fetching page 2")
+}
+
+func TestClient_IterBreak(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, txPage(1, 2))
+	})
+
+	for range c.BlocksMinedByAddressIter(context.Background(), "0x1", 2) {
+		break
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_IterResultWindow(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		fmt.Fprint(w, txPage(1, 4000))
+	})
+
+	end := 100
+	txs, err := Collect(c.NormalTxByAddressIter(context.Background(), "0x1", nil, &end, 4000, false))
+	assert.ErrorIs(t, err, response.ErrResultWindowExceeded)
+	assert.ErrorContains(t, err, "HistoryFetcher")
+	assert.Len(t, txs, 8000)
+	assert.Equal(t, []string{"1", "2"}, pages)
+}
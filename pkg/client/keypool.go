@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// ErrNoKeyAvailable is returned when every key of a KeyPool is benched
+var ErrNoKeyAvailable = errors.New("every API key in the pool is benched")
+
+// KeySelection decides which key of a KeyPool serves the next request
+type KeySelection int
+
+const (
+	// RoundRobin cycles through the keys in order
+	RoundRobin KeySelection = iota
+	// LeastUsed picks the key with the fewest calls so far
+	LeastUsed
+)
+
+// KeyUsage is the accounting of a single key in a KeyPool
+type KeyUsage struct {
+	Key string
+	// Calls made with the key
+	Calls int
+	// RateLimited calls rejected by etherscan for exceeding a limit
+	RateLimited int
+	// BenchedUntil is when the key becomes available again, zero if it is not benched
+	BenchedUntil time.Time
+}
+
+// KeyPool spreads requests across several API keys.
+// A key that hits a rate limit sits out for Cooldown,
+// one that hits its daily limit sits out until the next UTC day.
+//
+// A KeyPool is safe for concurrent use and may be shared by several clients.
+type KeyPool struct {
+	// Cooldown for a key rejected with a per-second rate limit, defaults to one second
+	Cooldown time.Duration
+
+	selection KeySelection
+
+	mu   sync.Mutex
+	keys []KeyUsage
+	next int
+
+	// now is swapped in tests
+	now func() time.Time
+}
+
+// NewKeyPool creates a KeyPool over keys
+func NewKeyPool(selection KeySelection, keys ...string) *KeyPool {
+	p := &KeyPool{
+		Cooldown:  time.Second,
+		selection: selection,
+		keys:      make([]KeyUsage, len(keys)),
+		now:       time.Now,
+	}
+	for i, key := range keys {
+		p.keys[i].Key = key
+	}
+	return p
+}
+
+// Usage returns a snapshot of per-key accounting, in the order keys were given
+func (p *KeyPool) Usage() []KeyUsage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	usage := append([]KeyUsage(nil), p.keys...)
+	for i := range usage {
+		if !usage[i].BenchedUntil.After(now) {
+			usage[i].BenchedUntil = time.Time{}
+		}
+	}
+	return usage
+}
+
+// pick chooses the key for the next call and counts the call against it
+func (p *KeyPool) pick() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	chosen := -1
+	for i := range p.keys {
+		idx := i
+		if p.selection == RoundRobin {
+			idx = (p.next + i) % len(p.keys)
+		}
+		if p.keys[idx].BenchedUntil.After(now) {
+			continue
+		}
+		if p.selection == RoundRobin {
+			chosen = idx
+			break
+		}
+		if chosen < 0 || p.keys[idx].Calls < p.keys[chosen].Calls {
+			chosen = idx
+		}
+	}
+	if chosen < 0 {
+		return "", ErrNoKeyAvailable
+	}
+
+	p.next = chosen + 1
+	p.keys[chosen].Calls++
+	return p.keys[chosen].Key, nil
+}
+
+// report benches key if err says it ran into a rate or daily limit
+func (p *KeyPool) report(key string, err error) {
+	var apiErr *response.Error
+	if !errors.As(err, &apiErr) || !errors.Is(apiErr, response.ErrRateLimited) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := range p.keys {
+		if p.keys[i].Key != key {
+			continue
+		}
+
+		now := p.now()
+		until := now.Add(p.Cooldown)
+		if apiErr.RetryAfter > 0 {
+			until = now.Add(apiErr.RetryAfter)
+		}
+		if strings.Contains(strings.ToLower(apiErr.Result), "daily") {
+			until = now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+		}
+
+		p.keys[i].RateLimited++
+		if until.After(p.keys[i].BenchedUntil) {
+			p.keys[i].BenchedUntil = until
+		}
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPool_RoundRobin(t *testing.T) {
+	p := NewKeyPool(RoundRobin, "a", "b", "c")
+
+	var picked []string
+	for i := 0; i < 4; i++ {
+		key, err := p.pick()
+		assert.NoError(t, err)
+		picked = append(picked, key)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a"}, picked)
+}
+
+func TestKeyPool_LeastUsed(t *testing.T) {
+	p := NewKeyPool(LeastUsed, "a", "b")
+	p.keys[0].Calls = 3
+
+	for i := 0; i < 3; i++ {
+		key, _ := p.pick()
+		assert.Equal(t, "b", key)
+	}
+	key, _ := p.pick()
+	assert.Equal(t, "a", key)
+}
+
+func TestKeyPool_Bench(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewKeyPool(RoundRobin, "a", "b")
+	p.now = func() time.Time { return now }
+
+	p.report("a", &response.Error{Kind: response.ErrRateLimited, Result: "Max calls per sec rate limit reached (5/sec)"})
+	p.report("b", &response.Error{Kind: response.ErrRateLimited, Result: "Max daily rate limit reached"})
+
+	_, err := p.pick()
+	assert.ErrorIs(t, err, ErrNoKeyAvailable)
+	assert.Equal(t, ClassLocalLimit, Classify(err))
+
+	usage := p.Usage()
+	assert.Equal(t, now.Add(time.Second), usage[0].BenchedUntil)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), usage[1].BenchedUntil)
+	assert.Equal(t, 1, usage[0].RateLimited)
+
+	now = now.Add(time.Second)
+	key, err := p.pick()
+	assert.NoError(t, err)
+	assert.Equal(t, "a", key)
+}
+
+func TestClient_KeyPool(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("apikey") == "limited" {
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Max calls per sec rate limit reached (5/sec)"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"1"}`)
+	})
+	c.keyPool = NewKeyPool(RoundRobin, "limited", "good")
+	c.retryPolicy = RetryPolicy{MaxAttempts: 2}
+
+	for i := 0; i < 3; i++ {
+		_, err := c.TokenTotalSupply("0x0000000000000000000000000000000000000000")
+		assert.NoError(t, err)
+	}
+
+	usage := c.keyPool.Usage()
+	assert.Equal(t, KeyUsage{Key: "limited", Calls: 1, RateLimited: 1, BenchedUntil: usage[0].BenchedUntil}, usage[0])
+	assert.Equal(t, 3, usage[1].Calls)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// redacted replaces API keys in logged URLs and returned errors
+const redacted = "REDACTED"
+
+// verboseLogger is used when Verbose is on and no Logger is configured
+var verboseLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+// log returns the logger to use, nil when logging is off
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	if c.Verbose {
+		return verboseLogger
+	}
+	return nil
+}
+
+// logRequest records a single round trip of req.
+// Successful requests are logged at debug level, failed ones at warn level.
+func (c *Client) logRequest(ctx context.Context, req *Request, start time.Time, body []byte, cached bool, err error) {
+	c.logResponse(ctx, req, start, len(body), body, cached, err)
+}
+
+// logResponse is logRequest for a response of size bytes, whose body may not be at hand,
+// as for streamed requests
+func (c *Client) logResponse(ctx context.Context, req *Request, start time.Time, size int, body []byte, cached bool, err error) {
+	logger := c.log()
+	if logger == nil {
+		return
+	}
+
+	status := http.StatusOK
+	var apiErr *response.Error
+	if errors.As(err, &apiErr) && apiErr.HTTPStatus != 0 {
+		status = apiErr.HTTPStatus
+	}
+
+	attrs := []slog.Attr{
+		slog.String("module", req.Module),
+		slog.String("action", req.Action),
+		slog.Int("chain", c.chain.ID()),
+		slog.Int("attempt", req.Attempt),
+		slog.Duration("latency", time.Since(start)),
+		slog.Bool("cached", cached),
+		slog.Int("size", size),
+	}
+	if !cached {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+	if c.Verbose {
+		attrs = append(attrs, slog.String("url", redactURL(c.craftURL(req.Module, req.Action, req.Values))))
+		if body != nil {
+			attrs = append(attrs, slog.String("body", string(body)))
+		}
+	}
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("class", Classify(err).String()), slog.String("error", err.Error()))
+	}
+	logger.LogAttrs(ctx, level, "etherscan request", attrs...)
+}
+
+// logRetry records that req is retried after err
+func (c *Client) logRetry(ctx context.Context, req *Request, delay time.Duration, err error) {
+	logger := c.log()
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "retrying etherscan request",
+		slog.String("module", req.Module),
+		slog.String("action", req.Action),
+		slog.Int("chain", c.chain.ID()),
+		slog.Int("attempt", req.Attempt),
+		slog.Duration("delay", delay),
+		slog.String("class", Classify(err).String()),
+		slog.String("error", err.Error()),
+	)
+}
+
+// redactURL hides the apikey parameter of raw
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	query := u.Query()
+	if !query.Has("apikey") {
+		return raw
+	}
+	query.Set("apikey", redacted)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/chain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Logger(t *testing.T) {
+	var out bytes.Buffer
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+	})
+	c.logger = slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c.Verbose = true
+
+	_, err := c.ContractABI("0x0000000000000000000000000000000000000000")
+	assert.Error(t, err)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "contract", record["module"])
+	assert.Equal(t, "getabi", record["action"])
+	assert.Equal(t, 1.0, record["chain"])
+	assert.Equal(t, 200.0, record["status"])
+	assert.Equal(t, "api", record["class"])
+	assert.Contains(t, record["url"], "apikey=REDACTED")
+	assert.NotContains(t, out.String(), "abc123")
+}
+
+func TestClient_RedactsTransportErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	c := NewCustomized(Customization{Key: "abc123", Chain: chain.EthereumMainnet, BaseURL: srv.URL})
+
+	_, err := c.GasOracle()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "apikey=REDACTED")
+	assert.NotContains(t, err.Error(), "abc123")
+}
+
+func TestRedactURL(t *testing.T) {
+	assert.Equal(t, "https://api.etherscan.io/v2/api?apikey=REDACTED&module=stats", redactURL("https://api.etherscan.io/v2/api?module=stats&apikey=secret"))
+	assert.Equal(t, "https://api.etherscan.io/v2/api?module=stats", redactURL("https://api.etherscan.io/v2/api?module=stats"))
+}
+
+func TestClient_LoggerStreamSize(t *testing.T) {
+	const body = `{"status":"1","message":"OK","result":[{"blockNumber":"1","timeStamp":"0","hash":"0xa"}]}`
+	var out bytes.Buffer
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	c.logger = slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := Collect(c.NormalTxByAddressStream(context.Background(), "0x1", nil, nil, 1, 10, false))
+	assert.NoError(t, err)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, float64(len(body)), record["size"])
+}
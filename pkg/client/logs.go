@@ -8,6 +8,8 @@
 package client
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 
@@ -20,19 +22,118 @@ type LogParams struct {
 	ToBlock   int    `json:"toBlock"`
 	Topic0    string `json:"topic0"`
 	Address   string `json:"address"`
+	// Page and Offset are only sent when positive, Offset is at most MaxLogsPerPage
+	Page   int `json:"page,omitempty"`
+	Offset int `json:"offset,omitempty"`
 }
 
+// MaxLogsPerPage is the most logs etherscan returns for a single getLogs request
+const MaxLogsPerPage = 1000
+
 func (p LogParams) GetUrlValues() url.Values {
+	return p.filter().GetUrlValues()
+}
+
+func (p LogParams) filter() LogFilter {
+	filter := LogFilter{
+		FromBlock: p.FromBlock,
+		ToBlock:   &p.ToBlock,
+		Address:   p.Address,
+		Page:      p.Page,
+		Offset:    p.Offset,
+	}
+	if p.Topic0 != "" {
+		filter.Topics = []string{p.Topic0}
+	}
+	return filter
+}
+
+// TopicOperator combines the conditions on two topics of a LogFilter
+type TopicOperator string
+
+const (
+	TopicAnd TopicOperator = "and"
+	TopicOr  TopicOperator = "or"
+)
+
+// LogFilter selects logs, much like the FilterQuery of eth_getLogs
+type LogFilter struct {
+	FromBlock int
+	// ToBlock is the last block included, nil for the latest one
+	ToBlock *int
+	// Address of the emitting contract, empty for any
+	Address string
+	// Topics holds the values of topic0 to topic3 in order, empty for any
+	Topics []string
+	// Operators combines the conditions on two topics, keyed by their positions,
+	// e.g. {0, 1}: TopicOr for topic0_1_opr=or. Etherscan defaults to TopicAnd.
+	Operators map[[2]int]TopicOperator
+	// Page and Offset are only sent when positive, Offset is at most MaxLogsPerPage
+	Page   int
+	Offset int
+}
+
+// GetUrlValues encodes f, leaving out whatever is empty
+func (f LogFilter) GetUrlValues() url.Values {
 	values := url.Values{}
-	values.Add("fromBlock", strconv.Itoa(p.FromBlock))
-	values.Add("toBlock", strconv.Itoa(p.ToBlock))
-	values.Add("topic0", p.Topic0)
-	values.Add("address", p.Address)
+	values.Add("fromBlock", strconv.Itoa(f.FromBlock))
+	if f.ToBlock != nil {
+		values.Add("toBlock", strconv.Itoa(*f.ToBlock))
+	} else {
+		values.Add("toBlock", "latest")
+	}
+	if f.Address != "" {
+		values.Add("address", f.Address)
+	}
+	for i, topic := range f.Topics {
+		if topic != "" {
+			values.Add("topic"+strconv.Itoa(i), topic)
+		}
+	}
+	for pair, operator := range f.Operators {
+		values.Add(fmt.Sprintf("topic%d_%d_opr", pair[0], pair[1]), string(operator))
+	}
+	if f.Page > 0 {
+		values.Add("page", strconv.Itoa(f.Page))
+	}
+	if f.Offset > 0 {
+		values.Add("offset", strconv.Itoa(f.Offset))
+	}
 	return values
 }
 
+// validate reports filters etherscan would reject or misread
+func (f LogFilter) validate() error {
+	if f.FromBlock < 0 {
+		return errors.Wrapf(response.ErrInvalidParams, "negative from block %d", f.FromBlock)
+	}
+	if f.ToBlock != nil && f.FromBlock > *f.ToBlock {
+		return errors.Wrapf(response.ErrInvalidParams, "from block %d is after to block %d", f.FromBlock, *f.ToBlock)
+	}
+	if len(f.Topics) > 4 {
+		return errors.Wrapf(response.ErrInvalidParams, "%d topics given, at most 4 allowed", len(f.Topics))
+	}
+	for pair, operator := range f.Operators {
+		if pair[0] < 0 || pair[0] >= pair[1] || pair[1] > 3 {
+			return errors.Wrapf(response.ErrInvalidParams, "invalid topic pair %d_%d", pair[0], pair[1])
+		}
+		if pair[1] >= len(f.Topics) || f.Topics[pair[0]] == "" || f.Topics[pair[1]] == "" {
+			return errors.Wrapf(response.ErrInvalidParams, "operator on topic pair %d_%d without both topics", pair[0], pair[1])
+		}
+		if operator != TopicAnd && operator != TopicOr {
+			return errors.Wrapf(response.ErrInvalidParams, "invalid topic operator %q", operator)
+		}
+	}
+	return nil
+}
+
 // GetLogs gets logs that match "topic" emitted by the specified "address" between the "fromBlock" and "toBlock"
 func (c *Client) GetLogs(fromBlock, toBlock int, address, topic string) ([]response.Log, error) {
+	return c.GetLogsContext(context.Background(), fromBlock, toBlock, address, topic)
+}
+
+// GetLogsContext is like GetLogs but bound to ctx
+func (c *Client) GetLogsContext(ctx context.Context, fromBlock, toBlock int, address, topic string) ([]response.Log, error) {
 	param := LogParams{
 		FromBlock: fromBlock,
 		ToBlock:   toBlock,
@@ -40,9 +141,160 @@ func (c *Client) GetLogs(fromBlock, toBlock int, address, topic string) ([]respo
 		Address:   address,
 	}
 
-	body, err := c.execute("logs", "getLogs", param.GetUrlValues())
+	result, err := call[[]response.Log](ctx, c, "logs", "getLogs", param.GetUrlValues())
 	if err != nil {
 		return nil, errors.Wrap(err, "executing GetLogs request")
 	}
-	return response.ReadResponse[[]response.Log](body)
+	return result, nil
+}
+
+// GetLogsPage is like GetLogs, returning the given page of offset logs.
+// offset is at most MaxLogsPerPage.
+func (c *Client) GetLogsPage(fromBlock, toBlock int, address, topic string, page, offset int) ([]response.Log, error) {
+	return c.GetLogsPageContext(context.Background(), fromBlock, toBlock, address, topic, page, offset)
+}
+
+// GetLogsPageContext is like GetLogsPage but bound to ctx
+func (c *Client) GetLogsPageContext(ctx context.Context, fromBlock, toBlock int, address, topic string, page, offset int) ([]response.Log, error) {
+	if offset > MaxLogsPerPage {
+		return nil, errors.Wrapf(response.ErrInvalidParams, "offset %d is above %d", offset, MaxLogsPerPage)
+	}
+	if page*offset > MaxResultWindow {
+		return nil, errors.Wrapf(response.ErrResultWindowExceeded, "page %d of %d logs", page, offset)
+	}
+	param := LogParams{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Topic0:    topic,
+		Address:   address,
+		Page:      page,
+		Offset:    offset,
+	}
+
+	result, err := call[[]response.Log](ctx, c, "logs", "getLogs", param.GetUrlValues())
+	if err != nil {
+		return nil, errors.Wrap(err, "executing GetLogsPage request")
+	}
+	return result, nil
+}
+
+// GetAllLogs is like GetLogs, but does not stop at MaxLogsPerPage:
+// a range whose page comes back full is split in halves, recursively,
+// and a single block is paged through, until every log is retrieved.
+func (c *Client) GetAllLogs(fromBlock, toBlock int, address, topic string) ([]response.Log, error) {
+	return c.GetAllLogsContext(context.Background(), fromBlock, toBlock, address, topic)
+}
+
+// GetAllLogsContext is like GetAllLogs but bound to ctx
+func (c *Client) GetAllLogsContext(ctx context.Context, fromBlock, toBlock int, address, topic string) ([]response.Log, error) {
+	param := LogParams{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Topic0:    topic,
+		Address:   address,
+	}
+
+	logs, err := c.allLogs(ctx, param.filter())
+	if err != nil {
+		return nil, errors.Wrap(err, "executing GetAllLogs request")
+	}
+	return logs, nil
+}
+
+// FilterLogs gets the logs matching filter, a single page of them
+func (c *Client) FilterLogs(filter LogFilter) ([]response.Log, error) {
+	return c.FilterLogsContext(context.Background(), filter)
+}
+
+// FilterLogsContext is like FilterLogs but bound to ctx
+func (c *Client) FilterLogsContext(ctx context.Context, filter LogFilter) ([]response.Log, error) {
+	if err := filter.validate(); err != nil {
+		return nil, errors.Wrap(err, "validating log filter")
+	}
+
+	result, err := call[[]response.Log](ctx, c, "logs", "getLogs", filter.GetUrlValues())
+	if err != nil {
+		return nil, errors.Wrap(err, "executing FilterLogs request")
+	}
+	return result, nil
+}
+
+// FilterAllLogs gets every log matching filter, whose Page and Offset are ignored.
+// See GetAllLogs.
+func (c *Client) FilterAllLogs(filter LogFilter) ([]response.Log, error) {
+	return c.FilterAllLogsContext(context.Background(), filter)
+}
+
+// FilterAllLogsContext is like FilterAllLogs but bound to ctx
+func (c *Client) FilterAllLogsContext(ctx context.Context, filter LogFilter) ([]response.Log, error) {
+	if err := filter.validate(); err != nil {
+		return nil, errors.Wrap(err, "validating log filter")
+	}
+
+	logs, err := c.allLogs(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "executing FilterAllLogs request")
+	}
+	return logs, nil
+}
+
+// allLogs retrieves every log matching filter between its FromBlock and ToBlock,
+// in ascending block order
+func (c *Client) allLogs(ctx context.Context, filter LogFilter) ([]response.Log, error) {
+	filter.Page, filter.Offset = 1, MaxLogsPerPage
+	logs, err := call[[]response.Log](ctx, c, "logs", "getLogs", filter.GetUrlValues())
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching blocks %d to %s", filter.FromBlock, toBlockText(filter.ToBlock))
+	}
+	if len(logs) < MaxLogsPerPage {
+		return logs, nil
+	}
+
+	if filter.ToBlock == nil {
+		// a range must be closed to be split
+		latest, err := c.latestBlock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		filter.ToBlock = &latest
+	}
+	from, to := filter.FromBlock, *filter.ToBlock
+
+	if from >= to {
+		// a single block can only be paged through, up to MaxResultWindow
+		for filter.Page++; ; filter.Page++ {
+			if filter.Page*MaxLogsPerPage > MaxResultWindow {
+				return nil, errors.Wrapf(response.ErrResultWindowExceeded, "block %d alone holds more than %d logs", from, MaxResultWindow)
+			}
+			page, err := call[[]response.Log](ctx, c, "logs", "getLogs", filter.GetUrlValues())
+			if err != nil {
+				return nil, errors.Wrapf(err, "fetching page %d of block %d", filter.Page, from)
+			}
+			logs = append(logs, page...)
+			if len(page) < MaxLogsPerPage {
+				return logs, nil
+			}
+		}
+	}
+
+	mid := from + (to-from)/2
+	lower, upper := filter, filter
+	lower.ToBlock, upper.FromBlock = &mid, mid+1
+
+	logs, err = c.allLogs(ctx, lower)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := c.allLogs(ctx, upper)
+	if err != nil {
+		return nil, err
+	}
+	return append(logs, rest...), nil
+}
+
+func toBlockText(toBlock *int) string {
+	if toBlock == nil {
+		return "latest"
+	}
+	return strconv.Itoa(*toBlock)
 }
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetAllLogs(t *testing.T) {
+	// 300 logs per block over blocks 0 to 9, and 2500 in block 7
+	perBlock := func(block int) int {
+		if block == 7 {
+			return 2500
+		}
+		return 300
+	}
+
+	var requests int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		from, _ := strconv.Atoi(q.Get("fromBlock"))
+		to, _ := strconv.Atoi(q.Get("toBlock"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		assert.Equal(t, MaxLogsPerPage, offset)
+
+		var logs []map[string]string
+		for block := from; block <= min(to, 9); block++ {
+			for i := 0; i < perBlock(block); i++ {
+				logs = append(logs, map[string]string{
+					"blockNumber": fmt.Sprintf("0x%x", block),
+					"logIndex":    fmt.Sprintf("0x%x", i),
+				})
+			}
+		}
+		start := min((page-1)*offset, len(logs))
+		end := min(page*offset, len(logs))
+		if start == end {
+			fmt.Fprint(w, `{"status":"0","message":"No records found","result":[]}`)
+			return
+		}
+		result, _ := json.Marshal(logs[start:end])
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, result)
+	})
+
+	logs, err := c.GetAllLogs(0, 20, "0x1", "0x2")
+	assert.NoError(t, err)
+	assert.Len(t, logs, 9*300+2500)
+
+	seen := map[[2]uint64]bool{}
+	for i, log := range logs {
+		key := [2]uint64{log.BlockNumber, log.LogIndex}
+		assert.False(t, seen[key], "duplicate %v", key)
+		seen[key] = true
+		if i > 0 {
+			assert.LessOrEqual(t, logs[i-1].BlockNumber, log.BlockNumber)
+		}
+	}
+	assert.Less(t, requests, 40)
+}
+
+func TestClient_GetAllLogs_ResultWindow(t *testing.T) {
+	var pages []int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pages = append(pages, page)
+		// block 5 never runs out of logs
+		logs := make([]map[string]string, MaxLogsPerPage)
+		for i := range logs {
+			logs[i] = map[string]string{"blockNumber": "0x5", "logIndex": fmt.Sprintf("0x%x", (page-1)*MaxLogsPerPage+i)}
+		}
+		result, _ := json.Marshal(logs)
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, result)
+	})
+
+	_, err := c.GetAllLogs(5, 5, "0x1", "")
+	assert.ErrorIs(t, err, response.ErrResultWindowExceeded)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, pages)
+}
+
+func TestClient_GetLogsPage_Limits(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid paging must not be sent")
+	})
+
+	_, err := c.GetLogsPage(0, 10, "0x1", "", 1, MaxLogsPerPage+1)
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+	_, err = c.GetLogsPage(0, 10, "0x1", "", 11, MaxLogsPerPage)
+	assert.ErrorIs(t, err, response.ErrResultWindowExceeded)
+}
+
+func TestLogParams_GetUrlValues(t *testing.T) {
+	values := LogParams{FromBlock: 1, ToBlock: 2}.GetUrlValues()
+	assert.False(t, values.Has("page"))
+	assert.False(t, values.Has("offset"))
+
+	values = LogParams{FromBlock: 1, ToBlock: 2, Page: 3, Offset: 100}.GetUrlValues()
+	assert.Equal(t, "3", values.Get("page"))
+	assert.Equal(t, "100", values.Get("offset"))
+}
+
+func TestLogFilter_GetUrlValues(t *testing.T) {
+	values := LogFilter{
+		FromBlock: 100,
+		Topics:    []string{"0xa", "", "0xc"},
+		Operators: map[[2]int]TopicOperator{{0, 2}: TopicOr},
+	}.GetUrlValues()
+	assert.Equal(t, url.Values{
+		"fromBlock":    {"100"},
+		"toBlock":      {"latest"},
+		"topic0":       {"0xa"},
+		"topic2":       {"0xc"},
+		"topic0_2_opr": {"or"},
+	}, values)
+
+	to := 200
+	values = LogFilter{FromBlock: 100, ToBlock: &to, Address: "0x1"}.GetUrlValues()
+	assert.Equal(t, "200", values.Get("toBlock"))
+	assert.Equal(t, "0x1", values.Get("address"))
+	assert.False(t, values.Has("topic0"))
+}
+
+func TestLogFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter LogFilter
+		valid  bool
+	}{
+		{"plain", LogFilter{Topics: []string{"0xa"}}, true},
+		{"operator", LogFilter{Topics: []string{"0xa", "0xb"}, Operators: map[[2]int]TopicOperator{{0, 1}: TopicAnd}}, true},
+		{"too many topics", LogFilter{Topics: []string{"0xa", "0xb", "0xc", "0xd", "0xe"}}, false},
+		{"reversed pair", LogFilter{Topics: []string{"0xa", "0xb"}, Operators: map[[2]int]TopicOperator{{1, 0}: TopicOr}}, false},
+		{"missing topic", LogFilter{Topics: []string{"0xa"}, Operators: map[[2]int]TopicOperator{{0, 1}: TopicOr}}, false},
+		{"unknown operator", LogFilter{Topics: []string{"0xa", "0xb"}, Operators: map[[2]int]TopicOperator{{0, 1}: "xor"}}, false},
+		{"negative from block", LogFilter{FromBlock: -1}, false},
+		{"reversed range", LogFilter{FromBlock: 10, ToBlock: new(int)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, response.ErrInvalidParams)
+			}
+		})
+	}
+}
+
+func TestClient_FilterLogs(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "getLogs", q.Get("action"))
+		assert.Equal(t, "latest", q.Get("toBlock"))
+		assert.Equal(t, "0xb", q.Get("topic1"))
+		assert.False(t, q.Has("address"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"0x1","logIndex":"0x0"}]}`)
+	})
+
+	logs, err := c.FilterLogs(LogFilter{FromBlock: 1, Topics: []string{"", "0xb"}})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+
+	_, err = c.FilterLogs(LogFilter{Topics: make([]string, 5)})
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+}
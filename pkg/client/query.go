@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// block types of MinedBlockParams
+const (
+	BlockTypeBlocks = "blocks"
+	BlockTypeUncles = "uncles"
+)
+
+func sortOrder(desc bool) string {
+	if desc {
+		return SortDesc
+	}
+	return SortAsc
+}
+
+// withDefaults returns p with Sort defaulting to SortAsc and Page to 1 when Offset is set
+func (p TxListParams) withDefaults() TxListParams {
+	p.Page, p.Sort = defaultPaging(p.Page, p.Offset, p.Sort)
+	return p
+}
+
+// validate reports params etherscan would reject
+func (p TxListParams) validate() error {
+	if p.Address == "" {
+		return errors.Wrap(response.ErrInvalidParams, "address is required")
+	}
+	return validateRange(p.StartBlock, p.EndBlock, p.Page, p.Offset, p.Sort)
+}
+
+// withDefaults is like TxListParams.withDefaults
+func (p TokenTransferParams) withDefaults() TokenTransferParams {
+	p.Page, p.Sort = defaultPaging(p.Page, p.Offset, p.Sort)
+	return p
+}
+
+// validate reports params etherscan would reject
+func (p TokenTransferParams) validate() error {
+	if (p.ContractAddress == nil || *p.ContractAddress == "") && (p.Address == nil || *p.Address == "") {
+		return errors.Wrap(response.ErrInvalidParams, "contract address or address is required")
+	}
+	return validateRange(p.StartBlock, p.EndBlock, p.Page, p.Offset, p.Sort)
+}
+
+// withDefaults returns p with BlockType defaulting to BlockTypeBlocks and Page to 1 when Offset is set
+func (p MinedBlockParams) withDefaults() MinedBlockParams {
+	p.Page, _ = defaultPaging(p.Page, p.Offset, "")
+	if p.BlockType == "" {
+		p.BlockType = BlockTypeBlocks
+	}
+	return p
+}
+
+// validate reports params etherscan would reject
+func (p MinedBlockParams) validate() error {
+	if p.Address == "" {
+		return errors.Wrap(response.ErrInvalidParams, "address is required")
+	}
+	if p.BlockType != BlockTypeBlocks && p.BlockType != BlockTypeUncles {
+		return errors.Wrapf(response.ErrInvalidParams, "block type %q is neither %q nor %q", p.BlockType, BlockTypeBlocks, BlockTypeUncles)
+	}
+	return validateRange(nil, nil, p.Page, p.Offset, SortAsc)
+}
+
+func defaultPaging(page, offset int, sort string) (int, string) {
+	if page == 0 && offset > 0 {
+		page = 1
+	}
+	if sort == "" {
+		sort = SortAsc
+	}
+	return page, sort
+}
+
+// listValues leaves out the zero page and offset GetUrlValues always sends,
+// so that etherscan applies its own defaults
+func listValues(values url.Values) url.Values {
+	if values.Get("page") == "0" {
+		values.Del("page")
+	}
+	if values.Get("offset") == "0" {
+		values.Del("offset")
+	}
+	return values
+}
+
+func validateRange(startBlock, endBlock *int, page, offset int, sort string) error {
+	if startBlock != nil && endBlock != nil && *startBlock > *endBlock {
+		return errors.Wrapf(response.ErrInvalidParams, "start block %d is after end block %d", *startBlock, *endBlock)
+	}
+	if page < 0 || offset < 0 {
+		return errors.Wrapf(response.ErrInvalidParams, "negative page %d or offset %d", page, offset)
+	}
+	if page*offset > MaxResultWindow {
+		return errors.Wrapf(response.ErrResultWindowExceeded, "page %d of %d records", page, offset)
+	}
+	if sort != SortAsc && sort != SortDesc {
+		return errors.Wrapf(response.ErrInvalidParams, "sort %q is neither %q nor %q", sort, SortAsc, SortDesc)
+	}
+	return nil
+}
+
+// txListParams gathers the arguments of the positional tx list methods
+func txListParams(address string, startBlock *int, endBlock *int, page int, offset int, desc bool) TxListParams {
+	return TxListParams{
+		Address:    address,
+		StartBlock: startBlock,
+		EndBlock:   endBlock,
+		Page:       page,
+		Offset:     offset,
+		Sort:       sortOrder(desc),
+	}
+}
+
+// tokenTransferParams gathers the arguments of the positional transfer list methods
+func tokenTransferParams(contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) TokenTransferParams {
+	return TokenTransferParams{
+		ContractAddress: contractAddress,
+		Address:         address,
+		StartBlock:      startBlock,
+		EndBlock:        endBlock,
+		Page:            page,
+		Offset:          offset,
+		Sort:            sortOrder(desc),
+	}
+}
+
+// query applies the defaults to p, validates it and encodes it for the List methods
+func (p TxListParams) query() (url.Values, error) {
+	p = p.withDefaults()
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return listValues(p.GetUrlValues()), nil
+}
+
+// query is like TxListParams.query
+func (p TokenTransferParams) query() (url.Values, error) {
+	p = p.withDefaults()
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return listValues(p.GetUrlValues()), nil
+}
+
+// query is like TxListParams.query
+func (p MinedBlockParams) query() (url.Values, error) {
+	p = p.withDefaults()
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return listValues(p.GetUrlValues()), nil
+}
+
+// accountList runs the account list action with values as they are.
+// The positional methods pass GetUrlValues of their arguments, unvalidated,
+// the List methods what query returns.
+func accountList[T response.EtherscanResponse](ctx context.Context, c *Client, action, name string, values url.Values) (T, error) {
+	result, err := call[T](ctx, c, "account", action, values)
+	if err != nil {
+		return emptyList[T](), errors.Wrapf(err, "executing %s request", name)
+	}
+	return result, nil
+}
+
+// NormalTxList gets a list of "normal" tx as selected by params.
+//
+// Sort defaults to SortAsc, and Page to 1 when Offset is set.
+// Without Page and Offset etherscan returns up to MaxResultWindow txs.
+//
+// Unlike NormalTxByAddress, which sends its arguments as they are,
+// params are validated and a zero Page or Offset is left out.
+func (c *Client) NormalTxList(params TxListParams) ([]response.NormalTx, error) {
+	return c.NormalTxListContext(context.Background(), params)
+}
+
+// NormalTxListContext is like NormalTxList but bound to ctx
+func (c *Client) NormalTxListContext(ctx context.Context, params TxListParams) ([]response.NormalTx, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.NormalTx{}, errors.Wrap(err, "validating NormalTxList params")
+	}
+	return accountList[[]response.NormalTx](ctx, c, "txlist", "NormalTxList", values)
+}
+
+// InternalTxList gets a list of "internal" tx as selected by params,
+// see NormalTxList
+func (c *Client) InternalTxList(params TxListParams) ([]response.InternalTx, error) {
+	return c.InternalTxListContext(context.Background(), params)
+}
+
+// InternalTxListContext is like InternalTxList but bound to ctx
+func (c *Client) InternalTxListContext(ctx context.Context, params TxListParams) ([]response.InternalTx, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.InternalTx{}, errors.Wrap(err, "validating InternalTxList params")
+	}
+	return accountList[[]response.InternalTx](ctx, c, "txlistinternal", "InternalTxList", values)
+}
+
+// ERC20TransferList gets a list of "erc20 - token transfer events" as selected by params,
+// which needs a ContractAddress, an Address or both. See NormalTxList for the defaults.
+func (c *Client) ERC20TransferList(params TokenTransferParams) ([]response.ERC20Transfer, error) {
+	return c.ERC20TransferListContext(context.Background(), params)
+}
+
+// ERC20TransferListContext is like ERC20TransferList but bound to ctx
+func (c *Client) ERC20TransferListContext(ctx context.Context, params TokenTransferParams) ([]response.ERC20Transfer, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.ERC20Transfer{}, errors.Wrap(err, "validating ERC20TransferList params")
+	}
+	return accountList[[]response.ERC20Transfer](ctx, c, "tokentx", "ERC20TransferList", values)
+}
+
+// ERC721TransferList gets a list of "erc721 - token transfer events" as selected by params,
+// see ERC20TransferList
+func (c *Client) ERC721TransferList(params TokenTransferParams) ([]response.ERC721Transfer, error) {
+	return c.ERC721TransferListContext(context.Background(), params)
+}
+
+// ERC721TransferListContext is like ERC721TransferList but bound to ctx
+func (c *Client) ERC721TransferListContext(ctx context.Context, params TokenTransferParams) ([]response.ERC721Transfer, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.ERC721Transfer{}, errors.Wrap(err, "validating ERC721TransferList params")
+	}
+	return accountList[[]response.ERC721Transfer](ctx, c, "tokennfttx", "ERC721TransferList", values)
+}
+
+// ERC1155TransferList gets a list of "erc1155 - token transfer events" as selected by params,
+// see ERC20TransferList
+func (c *Client) ERC1155TransferList(params TokenTransferParams) ([]response.ERC1155Transfer, error) {
+	return c.ERC1155TransferListContext(context.Background(), params)
+}
+
+// ERC1155TransferListContext is like ERC1155TransferList but bound to ctx
+func (c *Client) ERC1155TransferListContext(ctx context.Context, params TokenTransferParams) ([]response.ERC1155Transfer, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.ERC1155Transfer{}, errors.Wrap(err, "validating ERC1155TransferList params")
+	}
+	return accountList[[]response.ERC1155Transfer](ctx, c, "token1155tx", "ERC1155TransferList", values)
+}
+
+// BeaconWithdrawalList gets a list of beacon chain withdrawals to params.Address,
+// see NormalTxList
+func (c *Client) BeaconWithdrawalList(params TxListParams) ([]response.BeaconWithdrawal, error) {
+	return c.BeaconWithdrawalListContext(context.Background(), params)
+}
+
+// BeaconWithdrawalListContext is like BeaconWithdrawalList but bound to ctx
+func (c *Client) BeaconWithdrawalListContext(ctx context.Context, params TxListParams) ([]response.BeaconWithdrawal, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.BeaconWithdrawal{}, errors.Wrap(err, "validating BeaconWithdrawalList params")
+	}
+	return accountList[[]response.BeaconWithdrawal](ctx, c, "txsBeaconWithdrawal", "BeaconWithdrawalList", values)
+}
+
+// MinedBlockList gets a list of blocks or uncles mined by params.Address.
+//
+// BlockType defaults to BlockTypeBlocks, and Page to 1 when Offset is set.
+func (c *Client) MinedBlockList(params MinedBlockParams) ([]response.MinedBlock, error) {
+	return c.MinedBlockListContext(context.Background(), params)
+}
+
+// MinedBlockListContext is like MinedBlockList but bound to ctx
+func (c *Client) MinedBlockListContext(ctx context.Context, params MinedBlockParams) ([]response.MinedBlock, error) {
+	values, err := params.query()
+	if err != nil {
+		return []response.MinedBlock{}, errors.Wrap(err, "validating MinedBlockList params")
+	}
+	return accountList[[]response.MinedBlock](ctx, c, "getminedblocks", "MinedBlockList", values)
+}
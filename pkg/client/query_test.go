@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxListParams_Validate(t *testing.T) {
+	start, end := 10, 5
+	tests := []struct {
+		name   string
+		params TxListParams
+		kind   error
+	}{
+		{"defaults", TxListParams{Address: "0x1"}, nil},
+		{"paged", TxListParams{Address: "0x1", Offset: 100}, nil},
+		{"no address", TxListParams{}, response.ErrInvalidParams},
+		{"reversed range", TxListParams{Address: "0x1", StartBlock: &start, EndBlock: &end}, response.ErrInvalidParams},
+		{"negative page", TxListParams{Address: "0x1", Page: -1}, response.ErrInvalidParams},
+		{"window", TxListParams{Address: "0x1", Page: 11, Offset: 1000}, response.ErrResultWindowExceeded},
+		{"sort", TxListParams{Address: "0x1", Sort: "up"}, response.ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.withDefaults().validate()
+			if tt.kind == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestTokenTransferParams_Validate(t *testing.T) {
+	empty, contract := "", "0xc"
+	assert.ErrorIs(t, TokenTransferParams{}.withDefaults().validate(), response.ErrInvalidParams)
+	assert.ErrorIs(t, TokenTransferParams{Address: &empty}.withDefaults().validate(), response.ErrInvalidParams)
+	assert.NoError(t, TokenTransferParams{ContractAddress: &contract}.withDefaults().validate())
+}
+
+func TestClient_ERC20TransferList(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[]}`)
+	})
+
+	address := "0x1"
+	_, err := c.ERC20TransferList(TokenTransferParams{Address: &address, Offset: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, "action=tokentx&address=0x1&apikey=abc123&chainid=1&module=account&offset=50&page=1&sort=asc", query)
+
+	calls := query
+	_, err = c.ERC20TransferList(TokenTransferParams{})
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+	assert.Equal(t, calls, query, "invalid params are not sent")
+}
+
+func TestClient_LegacyListWire(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[]}`)
+	})
+
+	// the positional methods send what they always did, unvalidated
+	_, err := c.NormalTxByAddress("", nil, nil, 0, 0, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "action=txlist&apikey=abc123&chainid=1&module=account&offset=0&page=0&sort=desc", query)
+
+	_, err = c.ERC20Transfers(nil, nil, nil, nil, 11, 1000, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "action=tokentx&apikey=abc123&chainid=1&module=account&offset=1000&page=11&sort=asc", query)
+
+	// so do their streamed variants
+	_, err = Collect(c.NormalTxByAddressStream(context.Background(), "", nil, nil, 0, 0, true))
+	assert.NoError(t, err)
+	assert.Equal(t, "action=txlist&apikey=abc123&chainid=1&module=account&offset=0&page=0&sort=desc", query)
+
+	_, err = c.NormalTxList(TxListParams{Address: "0x1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "action=txlist&address=0x1&apikey=abc123&chainid=1&module=account&sort=asc", query)
+
+	_, err = Collect(c.NormalTxListStream(context.Background(), TxListParams{Address: "0x1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "action=txlist&address=0x1&apikey=abc123&chainid=1&module=account&sort=asc", query)
+}
+
+func TestMinedBlockParams_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params MinedBlockParams
+		kind   error
+	}{
+		{"defaults", MinedBlockParams{Address: "0x1"}, nil},
+		{"uncles", MinedBlockParams{Address: "0x1", BlockType: BlockTypeUncles, Offset: 10}, nil},
+		{"no address", MinedBlockParams{}, response.ErrInvalidParams},
+		{"block type", MinedBlockParams{Address: "0x1", BlockType: "ommers"}, response.ErrInvalidParams},
+		{"window", MinedBlockParams{Address: "0x1", Page: 101, Offset: 100}, response.ErrResultWindowExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.withDefaults().validate()
+			if tt.kind == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestClient_MinedBlockList(t *testing.T) {
+	var query string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"3462296","timeStamp":"1491118514","blockReward":"5194770940000000000"}]}`)
+	})
+
+	blocks, err := c.MinedBlockList(MinedBlockParams{Address: "0x1", Offset: 10})
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 1)
+	assert.Equal(t, "action=getminedblocks&address=0x1&apikey=abc123&blocktype=blocks&chainid=1&module=account&offset=10&page=1", query)
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRateLimitExceeded is returned by a fail-fast RateLimiter
+// when a call would exceed the configured Plan.
+var ErrRateLimitExceeded = errors.New("client-side rate limit exceeded")
+
+// ErrPlanConflict is returned by SharedRateLimiter when the key is already
+// limited by another Plan.
+var ErrPlanConflict = errors.New("key already rate limited with another plan")
+
+// Plan is the call budget of an Etherscan API plan.
+// The zero value means no limit.
+type Plan struct {
+	// PerSecond calls allowed in any one second
+	PerSecond int
+	// PerDay calls allowed per UTC calendar day
+	PerDay int
+}
+
+// Etherscan API plan presets, see https://etherscan.io/apis
+var (
+	PlanFree     = Plan{PerSecond: 5, PerDay: 100_000}
+	PlanStandard = Plan{PerSecond: 10, PerDay: 200_000}
+	PlanAdvanced = Plan{PerSecond: 20, PerDay: 500_000}
+	PlanPro      = Plan{PerSecond: 30, PerDay: 1_000_000}
+)
+
+// LimitPolicy decides what a RateLimiter does once a limit is reached
+type LimitPolicy int
+
+const (
+	// LimitBlock waits until the call fits in the budget, or ctx is done
+	LimitBlock LimitPolicy = iota
+	// LimitFailFast returns ErrRateLimitExceeded right away
+	LimitFailFast
+)
+
+// RateLimiter enforces both the per-second and the per-day budget of a Plan.
+// It is safe for concurrent use.
+//
+// Its policy is only a default, callers sharing a limiter may each use their own
+// through WaitPolicy.
+type RateLimiter struct {
+	plan   Plan
+	policy LimitPolicy
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	day      time.Time
+	dayCalls int
+
+	// now and sleep are swapped in tests
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimiter creates a RateLimiter for plan
+func NewRateLimiter(plan Plan, policy LimitPolicy) *RateLimiter {
+	return &RateLimiter{
+		plan:   plan,
+		policy: policy,
+		tokens: float64(plan.PerSecond),
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[string]*RateLimiter{}
+)
+
+// SharedRateLimiter returns the RateLimiter registered for key,
+// creating it with plan and LimitBlock on first use.
+//
+// Every client configured with the same key shares the budget of the returned limiter,
+// since Etherscan accounts calls per key rather than per connection.
+// Each picks its own LimitPolicy with WaitPolicy.
+// Asking for a key already registered with a different plan fails with ErrPlanConflict.
+func SharedRateLimiter(key string, plan Plan) (*RateLimiter, error) {
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+
+	if l, ok := sharedLimiters[key]; ok {
+		if l.plan != plan {
+			return nil, errors.Wrapf(ErrPlanConflict, "limited to %+v, asked for %+v", l.plan, plan)
+		}
+		return l, nil
+	}
+	l := NewRateLimiter(plan, LimitBlock)
+	sharedLimiters[key] = l
+	return l, nil
+}
+
+// Wait takes one call from the budget under the limiter's own policy.
+//
+// Under LimitBlock it sleeps until the call is allowed or ctx is done,
+// under LimitFailFast it returns ErrRateLimitExceeded instead of sleeping.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	return l.WaitPolicy(ctx, l.policy)
+}
+
+// WaitPolicy is like Wait, under policy instead of the limiter's own
+func (l *RateLimiter) WaitPolicy(ctx context.Context, policy LimitPolicy) error {
+	for {
+		delay, booked, err := l.reserve(policy)
+		if err != nil {
+			return err
+		}
+		if delay <= 0 {
+			return nil
+		}
+
+		if err := l.sleep(ctx, delay); err != nil {
+			if booked {
+				l.cancel()
+			}
+			return err
+		}
+		if booked {
+			return nil
+		}
+	}
+}
+
+// reserve books a call and reports how long the caller has to wait for it.
+// Nothing is booked while the day is used up, the caller waits for the next day
+// and tries again.
+func (l *RateLimiter) reserve(policy LimitPolicy) (delay time.Duration, booked bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.refill(now)
+
+	if l.plan.PerDay > 0 && l.dayCalls >= l.plan.PerDay {
+		if policy == LimitFailFast {
+			return 0, false, errors.Wrapf(ErrRateLimitExceeded, "%d calls per day", l.plan.PerDay)
+		}
+		return l.day.AddDate(0, 0, 1).Sub(now), false, nil
+	}
+
+	if l.plan.PerSecond <= 0 {
+		l.dayCalls++
+		return 0, true, nil
+	}
+
+	if l.tokens < 1 && policy == LimitFailFast {
+		return 0, false, errors.Wrapf(ErrRateLimitExceeded, "%d calls per second", l.plan.PerSecond)
+	}
+
+	l.tokens--
+	l.dayCalls++
+	if l.tokens >= 0 {
+		return 0, true, nil
+	}
+	return time.Duration(-l.tokens / float64(l.plan.PerSecond) * float64(time.Second)), true, nil
+}
+
+// cancel gives back a booked call abandoned because ctx is done
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.plan.PerSecond > 0 {
+		l.tokens++
+	}
+	if l.dayCalls > 0 {
+		l.dayCalls--
+	}
+}
+
+// refill tops up per-second tokens and rolls the day over, l.mu must be held
+func (l *RateLimiter) refill(now time.Time) {
+	if day := now.UTC().Truncate(24 * time.Hour); !day.Equal(l.day) {
+		l.day = day
+		l.dayCalls = 0
+	}
+
+	if !l.last.IsZero() && l.plan.PerSecond > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * float64(l.plan.PerSecond)
+		if burst := float64(l.plan.PerSecond); l.tokens > burst {
+			l.tokens = burst
+		}
+	}
+	l.last = now
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/chain"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock drives a RateLimiter without actually sleeping
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (f *fakeClock) install(l *RateLimiter) *RateLimiter {
+	l.now = func() time.Time { return f.now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		f.slept = append(f.slept, d)
+		f.now = f.now.Add(d)
+		return ctx.Err()
+	}
+	return l
+}
+
+func TestRateLimiter_PerSecond(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := clock.install(NewRateLimiter(Plan{PerSecond: 2}, LimitBlock))
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, l.Wait(context.Background()))
+	}
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.slept)
+}
+
+func TestRateLimiter_FailFast(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := clock.install(NewRateLimiter(Plan{PerSecond: 1, PerDay: 2}, LimitFailFast))
+
+	assert.NoError(t, l.Wait(context.Background()))
+	assert.ErrorIs(t, l.Wait(context.Background()), ErrRateLimitExceeded)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	clock.now = clock.now.Add(time.Second)
+	err := l.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+	assert.ErrorContains(t, err, "per day")
+	assert.Empty(t, clock.slept)
+}
+
+func TestRateLimiter_BlocksUntilNextDay(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)}
+	l := clock.install(NewRateLimiter(Plan{PerDay: 1}, LimitBlock))
+
+	assert.NoError(t, l.Wait(context.Background()))
+	assert.NoError(t, l.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{time.Hour}, clock.slept)
+}
+
+func TestRateLimiter_CancelGivesBack(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := clock.install(NewRateLimiter(Plan{PerSecond: 1}, LimitBlock))
+
+	assert.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.Wait(ctx), context.Canceled)
+	assert.Equal(t, 0.0, l.tokens)
+	assert.Equal(t, 1, l.dayCalls)
+}
+
+func TestClient_SharedRateLimiter(t *testing.T) {
+	key := fmt.Sprintf("shared-%d", time.Now().UnixNano())
+	config := Customization{
+		Key:       key,
+		Chain:     chain.EthereumMainnet,
+		RateLimit: Plan{PerSecond: 1, PerDay: 10},
+	}
+
+	a := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"1"}`)
+	})
+	config.BaseURL = a.baseURL
+	// a creates the limiter blocking, b still fails fast on the shared budget
+	a = NewCustomized(config)
+	config.RateLimitPolicy = LimitFailFast
+	b := NewCustomized(config)
+
+	limiterA, err := a.limiterFor(key)
+	assert.NoError(t, err)
+	limiterB, err := b.limiterFor(key)
+	assert.NoError(t, err)
+	assert.Same(t, limiterA, limiterB)
+
+	_, err = a.EtherTotalSupply()
+	assert.NoError(t, err)
+	_, err = b.EtherTotalSupply()
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+
+	config.RateLimit = PlanPro
+	_, err = NewCustomized(config).EtherTotalSupply()
+	assert.ErrorIs(t, err, ErrPlanConflict)
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// ErrorClass groups request failures for retry decisions
+type ErrorClass int
+
+const (
+	// ClassUnknown is anything not covered below, never retried by default
+	ClassUnknown ErrorClass = iota
+	// ClassNetwork is a transport failure: refused or reset connection, timeout, truncated body
+	ClassNetwork
+	// ClassRateLimited is response.ErrRateLimited, etherscan turning a request down
+	ClassRateLimited
+	// ClassServer is a 5xx HTTP status
+	ClassServer
+	// ClassClient is a 4xx HTTP status other than 429
+	ClassClient
+	// ClassAPI is an etherscan status 0 response other than rate limiting
+	ClassAPI
+	// ClassCanceled is a canceled or expired context outside the transport.
+	// A deadline hit while a request is in flight is a timeout and counts as ClassNetwork,
+	// retry stops anyway once the caller's context is done.
+	ClassCanceled
+	// ClassLocalLimit is ErrRateLimitExceeded or ErrNoKeyAvailable, the client holding
+	// a request back itself. Not retried by default, so LimitFailFast stays fast.
+	ClassLocalLimit
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassNetwork:
+		return "network"
+	case ClassRateLimited:
+		return "rate limited"
+	case ClassServer:
+		return "server"
+	case ClassClient:
+		return "client"
+	case ClassAPI:
+		return "api"
+	case ClassCanceled:
+		return "canceled"
+	case ClassLocalLimit:
+		return "local limit"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryPolicy retries failed requests with exponential backoff and jitter.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts including the first one, values below 2 disable retries
+	MaxAttempts int
+	// BaseDelay before the second attempt, doubled for every further attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff, not a Retry-After sent by the server
+	MaxDelay time.Duration
+	// Jitter in [0, 1] is the fraction of each delay that is randomized
+	Jitter float64
+	// Retryable tells which classes are retried,
+	// nil means ClassNetwork, ClassRateLimited and ClassServer.
+	Retryable map[ErrorClass]bool
+}
+
+// DefaultRetryPolicy is a reasonable policy for long running exports
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+	Jitter:      0.5,
+}
+
+var defaultRetryable = map[ErrorClass]bool{
+	ClassNetwork:     true,
+	ClassRateLimited: true,
+	ClassServer:      true,
+}
+
+// Classify tells which ErrorClass err returned by a Client method belongs to
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ClassUnknown
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ClassCanceled
+	}
+	// http.Client.Timeout errors wrap context.DeadlineExceeded too,
+	// which itself is a net.Error, so tell them apart here.
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() && netErr != context.DeadlineExceeded {
+		return ClassNetwork
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ClassCanceled
+	}
+	if errors.Is(err, ErrRateLimitExceeded) || errors.Is(err, ErrNoKeyAvailable) {
+		return ClassLocalLimit
+	}
+
+	var apiErr *response.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case errors.Is(apiErr, response.ErrRateLimited):
+			return ClassRateLimited
+		case apiErr.HTTPStatus >= 500:
+			return ClassServer
+		case apiErr.HTTPStatus != 0 && apiErr.HTTPStatus != http.StatusOK:
+			return ClassClient
+		default:
+			return ClassAPI
+		}
+	}
+
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ClassNetwork
+	}
+
+	return ClassUnknown
+}
+
+// delay returns how long to wait before attempt+1 after err,
+// and false when err should not be retried.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = defaultRetryable
+	}
+	if !retryable[Classify(err)] {
+		return 0, false
+	}
+
+	var apiErr *response.Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (backoff > p.MaxDelay || backoff < 0) {
+		backoff = p.MaxDelay
+	}
+	if backoff < 0 {
+		backoff = 0
+	}
+	if p.Jitter > 0 {
+		jitter := time.Duration(float64(backoff) * p.Jitter)
+		backoff = backoff - jitter + rand.N(jitter+1)
+	}
+	return backoff, true
+}
+
+// retry runs handler until it succeeds, fails with a non-retryable error,
+// or the policy runs out of attempts.
+//
+// Every attempt passes through the whole interceptor chain with its own copy of values,
+// Request.Attempt tells which one it is.
+func (c *Client) retry(ctx context.Context, handler Handler, module, action string, values url.Values) (interface{}, error) {
+	for attempt := 1; ; attempt++ {
+		req := &Request{
+			Module:  module,
+			Action:  action,
+			Values:  cloneValues(values),
+			Attempt: attempt,
+		}
+
+		outcome, err := handler(ctx, req)
+		if err == nil {
+			return outcome, nil
+		}
+
+		var permanent permanentError
+		delay, ok := c.retryPolicy.delay(attempt, err)
+		if !ok || ctx.Err() != nil || errors.As(err, &permanent) {
+			if attempt > 1 {
+				err = errors.Wrapf(err, "giving up after %d attempts", attempt)
+			}
+			return outcome, err
+		}
+
+		c.logRetry(ctx, req, delay, err)
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return outcome, errors.Wrapf(sleepErr, "waiting to retry %v", err)
+		}
+	}
+}
+
+// permanentError wraps an error that must not be retried, whatever its class
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// parseRetryAfter reads a Retry-After header in either seconds or HTTP-date form
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
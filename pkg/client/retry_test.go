@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/chain"
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Retry(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Max calls per sec rate limit reached (5/sec)"}`)
+		default:
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"7"}`)
+		}
+	})
+	c.retryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	var attempts []int
+	c.Use(func(ctx context.Context, req *Request, next Handler) (interface{}, error) {
+		attempts = append(attempts, req.Attempt)
+		return next(ctx, req)
+	})
+
+	supply, err := c.EtherTotalSupply()
+	assert.NoError(t, err)
+	assert.Equal(t, "7", supply.Int().String())
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestClient_RetryGivesUp(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+	})
+	c.retryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	_, err := c.EtherTotalSupply()
+	assert.ErrorContains(t, err, "Invalid API Key")
+	assert.Equal(t, ClassAPI, Classify(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	var calls int
+	var first time.Time
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		assert.GreaterOrEqual(t, time.Since(first), time.Second)
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"0x"}`)
+	})
+	c.retryPolicy = RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+	_, err := c.ContractABI("0x0000000000000000000000000000000000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	serverErr := response.NewHTTPError(http.StatusServiceUnavailable, "503 Service Unavailable", "", 0)
+
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		delay, ok := p.delay(attempt+1, serverErr)
+		assert.True(t, ok)
+		assert.Equal(t, want, delay)
+	}
+
+	_, ok := p.delay(10, serverErr)
+	assert.False(t, ok, "out of attempts")
+
+	_, ok = p.delay(1, response.NewHTTPError(http.StatusBadRequest, "400 Bad Request", "", 0))
+	assert.False(t, ok, "client errors are not retried by default")
+
+	p.Jitter = 0.5
+	delay, _ := p.delay(2, serverErr)
+	assert.GreaterOrEqual(t, delay, time.Second)
+	assert.LessOrEqual(t, delay, 2*time.Second)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, time.Minute, parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
+
+func TestClient_RetrySkipsLocalLimit(t *testing.T) {
+	var calls int
+	srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"1"}`)
+	})
+	c := NewCustomized(Customization{
+		Key:             fmt.Sprintf("local-%d", time.Now().UnixNano()),
+		Chain:           chain.EthereumMainnet,
+		BaseURL:         srv.baseURL,
+		RateLimit:       Plan{PerSecond: 1},
+		RateLimitPolicy: LimitFailFast,
+		Retry:           RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second},
+	})
+
+	_, err := c.EtherTotalSupply()
+	assert.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.EtherTotalSupply()
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+	assert.Equal(t, ClassLocalLimit, Classify(err))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_RetryTimeout(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"7"}`)
+	})
+	c := NewCustomized(Customization{
+		Timeout: 50 * time.Millisecond,
+		Key:     "abc123",
+		Chain:   chain.EthereumMainnet,
+		BaseURL: srv.baseURL,
+		Retry:   RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+	})
+
+	supply, err := c.EtherTotalSupply()
+	assert.NoError(t, err)
+	assert.Equal(t, "7", supply.Int().String())
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestClassify_Context(t *testing.T) {
+	assert.Equal(t, ClassCanceled, Classify(errors.Wrap(context.Canceled, "waiting")))
+	assert.Equal(t, ClassCanceled, Classify(errors.Wrap(context.DeadlineExceeded, "waiting")))
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// Progress reports a finished shard of a ShardedFetcher download
+type Progress struct {
+	// Shard is the index of the finished shard, Shards the total
+	Shard  int
+	Shards int
+	// FromBlock and ToBlock are the inclusive block range of the shard
+	FromBlock int
+	ToBlock   int
+	// Records fetched for the shard
+	Records int
+	// Done counts shards finished so far, this one included
+	Done int
+}
+
+// ShardedFetcher downloads a history by splitting its block range into shards
+// fetched concurrently, each one window by window like HistoryFetcher.
+//
+// Workers share the client, hence its rate limiter and key pool:
+// more workers than the plan allows calls per second buys nothing.
+type ShardedFetcher struct {
+	history *HistoryFetcher
+
+	// Workers fetching shards concurrently
+	Workers int
+	// Shards the range is split into, defaults to four per worker
+	// so that a busy stretch of blocks does not hold up the rest
+	Shards int
+	// OnProgress, when set, is called after every shard, one call at a time
+	OnProgress func(Progress)
+}
+
+// NewShardedFetcher creates a ShardedFetcher using c with workers concurrent workers
+func NewShardedFetcher(c *Client, workers int) *ShardedFetcher {
+	return &ShardedFetcher{
+		history: NewHistoryFetcher(c),
+		Workers: workers,
+	}
+}
+
+// History gives access to the HistoryFetcher each shard runs, e.g. to tune PageSize
+func (s *ShardedFetcher) History() *HistoryFetcher { return s.history }
+
+// fetchSharded fetches startBlock to endBlock shard by shard and merges the records with compare
+func fetchSharded[T interface{ GetBlockNumber() int }](ctx context.Context, s *ShardedFetcher, startBlock int, endBlock *int, fetch windowFetch[T], compare func(a, b T) int) ([]T, error) {
+	end := 0
+	if endBlock != nil {
+		end = *endBlock
+	} else {
+		latest, err := s.history.client.latestBlock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		end = latest
+	}
+	if end < startBlock {
+		return []T{}, nil
+	}
+
+	workers := max(s.Workers, 1)
+	shards := s.Shards
+	if shards <= 0 {
+		shards = workers * 4
+	}
+	shards = min(shards, end-startBlock+1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results  = make([][]T, shards)
+		jobs     = make(chan int)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+
+	span := (end - startBlock + 1) / shards
+	bounds := func(shard int) (int, int) {
+		lo := startBlock + shard*span
+		if shard == shards-1 {
+			return lo, end
+		}
+		return lo, lo + span - 1
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range jobs {
+				lo, hi := bounds(shard)
+				records, err := fetchHistory(ctx, s.history, lo, &hi, fetch)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = errors.Wrapf(err, "shard %d", shard)
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				results[shard] = records
+				done++
+				if s.OnProgress != nil {
+					s.OnProgress(Progress{Shard: shard, Shards: shards, FromBlock: lo, ToBlock: hi, Records: len(records), Done: done})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for shard := 0; shard < shards; shard++ {
+		select {
+		case jobs <- shard:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	merged := slices.Concat(results...)
+	slices.SortStableFunc(merged, compare)
+	return merged, nil
+}
+
+// NormalTxs returns every "normal" tx of address from startBlock to endBlock,
+// ordered by block number and transaction index. endBlock defaults to the latest block when nil.
+func (s *ShardedFetcher) NormalTxs(ctx context.Context, address string, startBlock int, endBlock *int) ([]response.NormalTx, error) {
+	return fetchSharded(ctx, s, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.NormalTx, error) {
+		return s.history.client.NormalTxByAddressContext(ctx, address, &start, &end, page, offset, false)
+	}, func(a, b response.NormalTx) int {
+		return cmp.Or(cmp.Compare(a.BlockNumber, b.BlockNumber), cmp.Compare(a.TransactionIndex, b.TransactionIndex))
+	})
+}
+
+// InternalTxs returns every "internal" tx of address, ordered by block number.
+// Etherscan gives no transaction index for these, so txs within a block keep
+// the order etherscan returned them in, only the traces of one tx are ordered by trace id.
+// See NormalTxs.
+func (s *ShardedFetcher) InternalTxs(ctx context.Context, address string, startBlock int, endBlock *int) ([]response.InternalTx, error) {
+	return fetchSharded(ctx, s, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.InternalTx, error) {
+		return s.history.client.InternalTxByAddressContext(ctx, address, &start, &end, page, offset, false)
+	}, func(a, b response.InternalTx) int {
+		if c := cmp.Compare(a.BlockNumber, b.BlockNumber); c != 0 || a.Hash != b.Hash {
+			return c
+		}
+		return compareTraceID(a.TraceID, b.TraceID)
+	})
+}
+
+// ERC20Transfers returns every erc20 transfer event matching contractAddress and/or address,
+// ordered by block number, transaction index and log index. See NormalTxs.
+func (s *ShardedFetcher) ERC20Transfers(ctx context.Context, contractAddress, address *string, startBlock int, endBlock *int) ([]response.ERC20Transfer, error) {
+	return fetchSharded(ctx, s, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.ERC20Transfer, error) {
+		return s.history.client.ERC20TransfersContext(ctx, contractAddress, address, &start, &end, page, offset, false)
+	}, func(a, b response.ERC20Transfer) int {
+		return cmp.Or(cmp.Compare(a.BlockNumber, b.BlockNumber), cmp.Compare(a.TransactionIndex, b.TransactionIndex), cmp.Compare(a.LogIndex, b.LogIndex))
+	})
+}
+
+// ERC721Transfers returns every erc721 transfer event matching contractAddress and/or address,
+// ordered like ERC20Transfers. See NormalTxs.
+func (s *ShardedFetcher) ERC721Transfers(ctx context.Context, contractAddress, address *string, startBlock int, endBlock *int) ([]response.ERC721Transfer, error) {
+	return fetchSharded(ctx, s, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.ERC721Transfer, error) {
+		return s.history.client.ERC721TransfersContext(ctx, contractAddress, address, &start, &end, page, offset, false)
+	}, func(a, b response.ERC721Transfer) int {
+		return cmp.Or(cmp.Compare(a.BlockNumber, b.BlockNumber), cmp.Compare(a.TransactionIndex, b.TransactionIndex), cmp.Compare(a.LogIndex, b.LogIndex))
+	})
+}
+
+// ERC1155Transfers returns every erc1155 transfer event matching contractAddress and/or address,
+// ordered like ERC20Transfers. See NormalTxs.
+func (s *ShardedFetcher) ERC1155Transfers(ctx context.Context, contractAddress, address *string, startBlock int, endBlock *int) ([]response.ERC1155Transfer, error) {
+	return fetchSharded(ctx, s, startBlock, endBlock, func(ctx context.Context, start, end, page, offset int) ([]response.ERC1155Transfer, error) {
+		return s.history.client.ERC1155TransfersContext(ctx, contractAddress, address, &start, &end, page, offset, false)
+	}, func(a, b response.ERC1155Transfer) int {
+		return cmp.Or(cmp.Compare(a.BlockNumber, b.BlockNumber), cmp.Compare(a.TransactionIndex, b.TransactionIndex), cmp.Compare(a.LogIndex, b.LogIndex))
+	})
+}
+
+// compareTraceID orders trace ids like "0_1_10" numerically, part by part
+func compareTraceID(a, b string) int {
+	for a != "" || b != "" {
+		var pa, pb string
+		pa, a, _ = strings.Cut(a, "_")
+		pb, b, _ = strings.Cut(b, "_")
+		if c := cmp.Or(cmp.Compare(len(pa), len(pb)), cmp.Compare(pa, pb)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedFetcher_NormalTxs(t *testing.T) {
+	const blocks, perBlock = 400, 3
+
+	var (
+		mu     sync.Mutex
+		ranges [][2]int
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		start, _ := strconv.Atoi(q.Get("startblock"))
+		end, _ := strconv.Atoi(q.Get("endblock"))
+		mu.Lock()
+		ranges = append(ranges, [2]int{start, end})
+		mu.Unlock()
+
+		var txs []map[string]string
+		for block := start; block <= min(end, blocks-1); block++ {
+			// served out of transaction order, the merge sorts them
+			for i := perBlock - 1; i >= 0; i-- {
+				txs = append(txs, map[string]string{
+					"blockNumber":      strconv.Itoa(block),
+					"transactionIndex": strconv.Itoa(i),
+					"timeStamp":        "0",
+					"hash":             fmt.Sprintf("0x%d_%d", block, i),
+				})
+			}
+		}
+		result, _ := json.Marshal(txs)
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, result)
+	})
+
+	var progress []Progress
+	s := NewShardedFetcher(c, 4)
+	s.Shards = 7
+	s.OnProgress = func(p Progress) { progress = append(progress, p) }
+
+	end := blocks - 1
+	txs, err := s.NormalTxs(context.Background(), "0x1", 0, &end)
+	assert.NoError(t, err)
+	assert.Len(t, txs, blocks*perBlock)
+	for i, tx := range txs {
+		assert.Equal(t, i/perBlock, tx.BlockNumber)
+		assert.Equal(t, i%perBlock, tx.TransactionIndex)
+	}
+
+	assert.Len(t, ranges, 7)
+	assert.Len(t, progress, 7)
+	covered := 0
+	for i, p := range progress {
+		assert.Equal(t, i+1, p.Done)
+		assert.Equal(t, 7, p.Shards)
+		assert.Equal(t, (p.ToBlock-p.FromBlock+1)*perBlock, p.Records)
+		covered += p.ToBlock - p.FromBlock + 1
+	}
+	assert.Equal(t, blocks, covered)
+}
+
+func TestShardedFetcher_Error(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("startblock") == "50" {
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[]}`)
+	})
+	c.retryPolicy = RetryPolicy{MaxAttempts: 1}
+
+	s := NewShardedFetcher(c, 2)
+	s.Shards = 4
+	end := 199
+	_, err := s.InternalTxs(context.Background(), "0x1", 0, &end)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, response.ErrInvalidAPIKey)
+}
+
+func TestCompareTraceID(t *testing.T) {
+	assert.Equal(t, 0, compareTraceID("0_1", "0_1"))
+	assert.Equal(t, -1, compareTraceID("0_2", "0_10"))
+	assert.Equal(t, -1, compareTraceID("0", "0_1"))
+	assert.Equal(t, 1, compareTraceID("1", "0_5"))
+}
+
+func TestShardedFetcher_Order(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("startblock") != "0" {
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[]}`)
+			return
+		}
+		switch r.URL.Query().Get("action") {
+		case "tokentx":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[
+				{"blockNumber":"1","timeStamp":"0","hash":"0xb","transactionIndex":"2","logIndex":"9"},
+				{"blockNumber":"1","timeStamp":"0","hash":"0xa","transactionIndex":"1","logIndex":"5"},
+				{"blockNumber":"1","timeStamp":"0","hash":"0xb","transactionIndex":"2","logIndex":"7"}]}`)
+		case "txlistinternal":
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":[
+				{"blockNumber":"1","timeStamp":"0","hash":"0xb","traceId":"0"},
+				{"blockNumber":"1","timeStamp":"0","hash":"0xa","traceId":"0_10"},
+				{"blockNumber":"1","timeStamp":"0","hash":"0xa","traceId":"0_2"}]}`)
+		}
+	})
+
+	s := NewShardedFetcher(c, 2)
+	s.Shards = 2
+	end := 9
+	address := "0x1"
+
+	transfers, err := s.ERC20Transfers(context.Background(), nil, &address, 0, &end)
+	assert.NoError(t, err)
+	var order [][2]int
+	for _, transfer := range transfers {
+		order = append(order, [2]int{transfer.TransactionIndex, transfer.LogIndex})
+	}
+	assert.Equal(t, [][2]int{{1, 5}, {2, 7}, {2, 9}}, order)
+
+	internals, err := s.InternalTxs(context.Background(), address, 0, &end)
+	assert.NoError(t, err)
+	var traces []string
+	for _, tx := range internals {
+		traces = append(traces, tx.Hash+"/"+tx.TraceID)
+	}
+	// 0xb comes first in the response and stays first, despite sorting after 0xa
+	assert.Equal(t, []string{"0xb/0", "0xa/0_2", "0xa/0_10"}, traces)
+}
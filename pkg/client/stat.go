@@ -8,7 +8,9 @@
 package client
 
 import (
+	"context"
 	"net/url"
+	"strconv"
 
 	"github.com/TokenTax/etherscan-api/v2/internal/types"
 	"github.com/TokenTax/etherscan-api/v2/pkg/response"
@@ -19,6 +21,11 @@ type TokenTotalSupplyParams struct {
 	ContractAddress string `json:"contractaddress"`
 }
 
+type TokenSupplyHistoryParams struct {
+	ContractAddress string `json:"contractaddress"`
+	BlockNo         int    `json:"blockno"`
+}
+
 func (p TokenTotalSupplyParams) GetUrlValues() url.Values {
 	values := url.Values{}
 	if p.ContractAddress != "" {
@@ -27,31 +34,74 @@ func (p TokenTotalSupplyParams) GetUrlValues() url.Values {
 	return values
 }
 
+func (p TokenSupplyHistoryParams) GetUrlValues() url.Values {
+	values := url.Values{}
+	if p.ContractAddress != "" {
+		values.Add("contractaddress", p.ContractAddress)
+	}
+	values.Add("blockno", strconv.Itoa(p.BlockNo))
+	return values
+}
+
 // EtherTotalSupply gets total supply of ether
 func (c *Client) EtherTotalSupply() (totalSupply types.BigInt, err error) {
-	body, err := c.execute("stats", "ethsupply", nil)
+	return c.EtherTotalSupplyContext(context.Background())
+}
+
+// EtherTotalSupplyContext is like EtherTotalSupply but bound to ctx
+func (c *Client) EtherTotalSupplyContext(ctx context.Context) (totalSupply types.BigInt, err error) {
+	result, err := call[types.BigInt](ctx, c, "stats", "ethsupply", nil)
 	if err != nil {
 		return types.BigInt{}, errors.Wrap(err, "executing ExecutionStatus request")
 	}
-	return response.ReadResponse[types.BigInt](body)
+	return result, nil
 }
 
 // EtherLatestPrice gets the latest ether price, in BTC and USD
 func (c *Client) EtherLatestPrice() (price response.LatestPrice, err error) {
-	body, err := c.execute("stats", "ethprice", nil)
+	return c.EtherLatestPriceContext(context.Background())
+}
+
+// EtherLatestPriceContext is like EtherLatestPrice but bound to ctx
+func (c *Client) EtherLatestPriceContext(ctx context.Context) (price response.LatestPrice, err error) {
+	result, err := call[response.LatestPrice](ctx, c, "stats", "ethprice", nil)
 	if err != nil {
 		return response.LatestPrice{}, errors.Wrap(err, "executing LatestPrice request")
 	}
-	return response.ReadResponse[response.LatestPrice](body)
+	return result, nil
 }
 
 // TokenTotalSupply gets total supply of token on specified contract address
 func (c *Client) TokenTotalSupply(contractAddress string) (types.BigInt, error) {
+	return c.TokenTotalSupplyContext(context.Background(), contractAddress)
+}
+
+// TokenTotalSupplyContext is like TokenTotalSupply but bound to ctx
+func (c *Client) TokenTotalSupplyContext(ctx context.Context, contractAddress string) (types.BigInt, error) {
 	values := TokenTotalSupplyParams{ContractAddress: contractAddress}
 
-	body, err := c.execute("stats", "tokensupply", values.GetUrlValues())
+	result, err := call[types.BigInt](ctx, c, "stats", "tokensupply", values.GetUrlValues())
 	if err != nil {
 		return types.BigInt{}, errors.Wrap(err, "executing TokenTotalSupply request")
 	}
-	return response.ReadResponse[types.BigInt](body)
+	return result, nil
+}
+
+// TokenTotalSupplyAtBlock gets total supply of token on specified contract address as of blockNo.
+//
+// The tokensupplyhistory endpoint needs a paid plan, other keys get an error
+// matching response.ErrProOnly.
+func (c *Client) TokenTotalSupplyAtBlock(contractAddress string, blockNo int) (types.BigInt, error) {
+	return c.TokenTotalSupplyAtBlockContext(context.Background(), contractAddress, blockNo)
+}
+
+// TokenTotalSupplyAtBlockContext is like TokenTotalSupplyAtBlock but bound to ctx
+func (c *Client) TokenTotalSupplyAtBlockContext(ctx context.Context, contractAddress string, blockNo int) (types.BigInt, error) {
+	values := TokenSupplyHistoryParams{ContractAddress: contractAddress, BlockNo: blockNo}
+
+	result, err := call[types.BigInt](ctx, c, "stats", "tokensupplyhistory", values.GetUrlValues())
+	if err != nil {
+		return types.BigInt{}, errors.Wrap(err, "executing TokenTotalSupplyAtBlock request")
+	}
+	return result, nil
 }
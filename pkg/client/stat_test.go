@@ -0,0 +1,30 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_TokenTotalSupplyAtBlock(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "stats", q.Get("module"))
+		assert.Equal(t, "tokensupplyhistory", q.Get("action"))
+		assert.Equal(t, "0xc", q.Get("contractaddress"))
+		assert.Equal(t, "8000000", q.Get("blockno"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":"21265524714464"}`)
+	})
+	supply, err := c.TokenTotalSupplyAtBlock("0xc", 8000000)
+	assert.NoError(t, err)
+	assert.Equal(t, "21265524714464", supply.Int().String())
+
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Sorry, it looks like you are trying to access an API Pro endpoint. Contact us to upgrade to API Pro."}`)
+	})
+	_, err = c.TokenTotalSupplyAtBlock("0xc", 8000000)
+	assert.ErrorIs(t, err, response.ErrProOnly)
+}
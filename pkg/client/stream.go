@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// errStopStream tells the decoder the consumer of a stream is done with it
+var errStopStream = errors.New("stream stopped")
+
+// stream runs a list request through the interceptor chain and the retry policy like call,
+// but decodes its result element by element straight from the response body,
+// yielding every element instead of collecting them in a slice.
+//
+// Streamed requests skip the cache and coalescing, and interceptors see a nil outcome.
+// A failed attempt is only retried as long as none of its elements was yielded.
+func stream[T any](ctx context.Context, c *Client, module, action string, values url.Values) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var yielded, stopped bool
+		handler := c.handler(func(ctx context.Context, req *Request) (interface{}, error) {
+			err := streamTrip(ctx, c, req, func(item T) error {
+				yielded = true
+				if !yield(item, nil) {
+					stopped = true
+					return errStopStream
+				}
+				return nil
+			})
+			if stopped {
+				return nil, nil
+			}
+			if err != nil && yielded {
+				return nil, permanentError{err}
+			}
+			return nil, err
+		})
+
+		if _, err := c.retry(ctx, handler, module, action, values); err != nil && !stopped {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// streamTrip is roundTrip for stream, handing every element of the result to fn
+func streamTrip[T any](ctx context.Context, c *Client, req *Request, fn func(T) error) error {
+	values, key, err := c.acquire(ctx, req.Values)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	var body countingReader
+	res, err := c.send(ctx, req.Module, req.Action, values)
+	if err == nil {
+		body.r = res.Body
+		err = response.StreamResponse(&body, fn)
+		res.Body.Close()
+	}
+
+	var apiErr *response.Error
+	if errors.As(err, &apiErr) {
+		if errors.Is(apiErr, response.ErrNotFound) && apiErr.HTTPStatus == 0 && listActions[req.Module+"/"+req.Action] {
+			err = nil
+		} else {
+			if apiErr.HTTPStatus == 0 {
+				apiErr.HTTPStatus = http.StatusOK
+			}
+			apiErr.Module, apiErr.Action = req.Module, req.Action
+		}
+	}
+
+	reported := err
+	if errors.Is(err, errStopStream) {
+		reported = nil
+	}
+	if c.keyPool != nil {
+		c.keyPool.report(key, reported)
+	}
+	c.logResponse(ctx, req, start, body.n, nil, false, reported)
+	return err
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+// failedStream yields err alone
+func failedStream[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, err)
+	}
+}
+
+// NormalTxListStream is like NormalTxListContext, but decodes the page
+// tx by tx as it is read instead of holding all of it in memory.
+// Breaking out of the loop stops reading the response.
+func (c *Client) NormalTxListStream(ctx context.Context, params TxListParams) iter.Seq2[response.NormalTx, error] {
+	values, err := params.query()
+	if err != nil {
+		return failedStream[response.NormalTx](errors.Wrap(err, "validating NormalTxListStream params"))
+	}
+	return stream[response.NormalTx](ctx, c, "account", "txlist", values)
+}
+
+// InternalTxListStream is like InternalTxListContext, streamed.
+// See NormalTxListStream.
+func (c *Client) InternalTxListStream(ctx context.Context, params TxListParams) iter.Seq2[response.InternalTx, error] {
+	values, err := params.query()
+	if err != nil {
+		return failedStream[response.InternalTx](errors.Wrap(err, "validating InternalTxListStream params"))
+	}
+	return stream[response.InternalTx](ctx, c, "account", "txlistinternal", values)
+}
+
+// ERC20TransferListStream is like ERC20TransferListContext, streamed.
+// See NormalTxListStream.
+func (c *Client) ERC20TransferListStream(ctx context.Context, params TokenTransferParams) iter.Seq2[response.ERC20Transfer, error] {
+	values, err := params.query()
+	if err != nil {
+		return failedStream[response.ERC20Transfer](errors.Wrap(err, "validating ERC20TransferListStream params"))
+	}
+	return stream[response.ERC20Transfer](ctx, c, "account", "tokentx", values)
+}
+
+// ERC721TransferListStream is like ERC721TransferListContext, streamed.
+// See NormalTxListStream.
+func (c *Client) ERC721TransferListStream(ctx context.Context, params TokenTransferParams) iter.Seq2[response.ERC721Transfer, error] {
+	values, err := params.query()
+	if err != nil {
+		return failedStream[response.ERC721Transfer](errors.Wrap(err, "validating ERC721TransferListStream params"))
+	}
+	return stream[response.ERC721Transfer](ctx, c, "account", "tokennfttx", values)
+}
+
+// ERC1155TransferListStream is like ERC1155TransferListContext, streamed.
+// See NormalTxListStream.
+func (c *Client) ERC1155TransferListStream(ctx context.Context, params TokenTransferParams) iter.Seq2[response.ERC1155Transfer, error] {
+	values, err := params.query()
+	if err != nil {
+		return failedStream[response.ERC1155Transfer](errors.Wrap(err, "validating ERC1155TransferListStream params"))
+	}
+	return stream[response.ERC1155Transfer](ctx, c, "account", "token1155tx", values)
+}
+
+// NormalTxByAddressStream is like NormalTxByAddressContext, streamed.
+// Like it, and unlike NormalTxListStream, it sends its arguments unvalidated.
+func (c *Client) NormalTxByAddressStream(ctx context.Context, address string, startBlock *int, endBlock *int, page int, offset int, desc bool) iter.Seq2[response.NormalTx, error] {
+	params := txListParams(address, startBlock, endBlock, page, offset, desc)
+	return stream[response.NormalTx](ctx, c, "account", "txlist", params.GetUrlValues())
+}
+
+// InternalTxByAddressStream is like InternalTxByAddressContext, streamed.
+// See NormalTxByAddressStream.
+func (c *Client) InternalTxByAddressStream(ctx context.Context, address string, startBlock *int, endBlock *int, page int, offset int, desc bool) iter.Seq2[response.InternalTx, error] {
+	params := txListParams(address, startBlock, endBlock, page, offset, desc)
+	return stream[response.InternalTx](ctx, c, "account", "txlistinternal", params.GetUrlValues())
+}
+
+// ERC20TransfersStream is like ERC20TransfersContext, streamed.
+// See NormalTxByAddressStream.
+func (c *Client) ERC20TransfersStream(ctx context.Context, contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) iter.Seq2[response.ERC20Transfer, error] {
+	params := tokenTransferParams(contractAddress, address, startBlock, endBlock, page, offset, desc)
+	return stream[response.ERC20Transfer](ctx, c, "account", "tokentx", params.GetUrlValues())
+}
+
+// ERC721TransfersStream is like ERC721TransfersContext, streamed.
+// See NormalTxByAddressStream.
+func (c *Client) ERC721TransfersStream(ctx context.Context, contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) iter.Seq2[response.ERC721Transfer, error] {
+	params := tokenTransferParams(contractAddress, address, startBlock, endBlock, page, offset, desc)
+	return stream[response.ERC721Transfer](ctx, c, "account", "tokennfttx", params.GetUrlValues())
+}
+
+// ERC1155TransfersStream is like ERC1155TransfersContext, streamed.
+// See NormalTxByAddressStream.
+func (c *Client) ERC1155TransfersStream(ctx context.Context, contractAddress, address *string, startBlock *int, endBlock *int, page int, offset int, desc bool) iter.Seq2[response.ERC1155Transfer, error] {
+	params := tokenTransferParams(contractAddress, address, startBlock, endBlock, page, offset, desc)
+	return stream[response.ERC1155Transfer](ctx, c, "account", "token1155tx", params.GetUrlValues())
+}
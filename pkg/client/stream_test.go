@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_NormalTxByAddressStream(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "txlist", r.URL.Query().Get("action"))
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"1","timeStamp":"0","hash":"0xa"},{"blockNumber":"2","timeStamp":"0","hash":"0xb"}]}`)
+	})
+
+	var hashes []string
+	for tx, err := range c.NormalTxByAddressStream(context.Background(), "0x1", nil, nil, 1, 100, false) {
+		assert.NoError(t, err)
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.Equal(t, []string{"0xa", "0xb"}, hashes)
+}
+
+func TestClient_StreamBreak(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"1","timeStamp":"0"},{"blockNumber":"2","timeStamp":"0"}]}`)
+	})
+
+	address := "0x1"
+	seen := 0
+	for _, err := range c.ERC20TransfersStream(context.Background(), nil, &address, nil, nil, 1, 100, false) {
+		assert.NoError(t, err)
+		seen++
+		break
+	}
+	assert.Equal(t, 1, seen)
+}
+
+func TestClient_StreamErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+	})
+	items, err := Collect(c.InternalTxByAddressStream(context.Background(), "0x1", nil, nil, 1, 100, false))
+	assert.Empty(t, items)
+	assert.ErrorIs(t, err, response.ErrInvalidAPIKey)
+
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+	})
+	items, err = Collect(c.InternalTxByAddressStream(context.Background(), "0x1", nil, nil, 1, 100, false))
+	assert.Empty(t, items)
+	assert.NoError(t, err)
+}
+
+func TestClient_StreamRetry(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		// cut off after the first element, which must not be retried
+		fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"1","timeStamp":"0"},{"blockNumber":`)
+	})
+	c.retryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	txs, err := Collect(c.NormalTxByAddressStream(context.Background(), "0x1", nil, nil, 1, 100, false))
+	assert.Error(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_ListStreamValidates(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "1", r.URL.Query().Get("page"), "page defaults to 1")
+		fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+	})
+
+	_, err := Collect(c.NormalTxListStream(context.Background(), TxListParams{Address: "0x1", Offset: 100}))
+	assert.NoError(t, err)
+
+	_, err = Collect(c.NormalTxListStream(context.Background(), TxListParams{}))
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+	_, err = Collect(c.ERC721TransferListStream(context.Background(), TokenTransferParams{Page: 11, Offset: 1000}))
+	assert.ErrorIs(t, err, response.ErrInvalidParams)
+	assert.Equal(t, 1, calls, "invalid params are not sent")
+}
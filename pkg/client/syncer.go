@@ -0,0 +1,349 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+)
+
+// DefaultSyncOverlap is how many blocks a Syncer fetches again by default
+const DefaultSyncOverlap = 64
+
+// Syncer hands out the records of an address incrementally:
+// each sync only fetches what came after the previous one, as recorded in a Checkpoint.
+//
+// The last Overlap blocks already synced are fetched again, so records etherscan
+// indexed late are not missed. Records handed out before are recognized by hash,
+// trace id or transfer details and skipped, so every record is handed out once.
+//
+// With a ConfirmationDepth, records handed out from blocks not that deep yet are
+// checked again by later syncs, and retracted through OnRetract when their block
+// was reorganized away or they are gone.
+type Syncer struct {
+	client     *Client
+	history    *HistoryFetcher
+	checkpoint Checkpoint
+
+	// Overlap is the number of already synced blocks fetched again
+	Overlap int
+	// StartBlock is where the first sync of an address starts
+	StartBlock int
+
+	// ConfirmationDepth is how many blocks deep a record must be to be final,
+	// zero disables reorg detection
+	ConfirmationDepth int
+	// OnRetract, when set, is called for every record handed out before
+	// that a sync finds reorganized or gone, before any new record is handed out.
+	// A failed OnRetract fails the sync, which retracts the same records next time.
+	OnRetract func(Retraction) error
+}
+
+// RetractReason tells why a record is retracted
+type RetractReason string
+
+const (
+	// RetractReorged is for a record now listed in another block, or with another block hash
+	RetractReorged RetractReason = "reorged"
+	// RetractDisappeared is for a record no longer listed at all
+	RetractDisappeared RetractReason = "disappeared"
+)
+
+// Retraction revokes a record a Syncer handed out before
+type Retraction struct {
+	// Key of the sync, see SyncState
+	Key    string
+	Reason RetractReason
+	// ID, Block and BlockHash of the record as handed out
+	ID        string
+	Block     int
+	BlockHash string
+	// Record as handed out, e.g. a response.NormalTx
+	Record interface{}
+}
+
+// NewSyncer creates a Syncer using c, keeping its progress in checkpoint
+func NewSyncer(c *Client, checkpoint Checkpoint) *Syncer {
+	return &Syncer{
+		client:     c,
+		history:    NewHistoryFetcher(c),
+		checkpoint: checkpoint,
+		Overlap:    DefaultSyncOverlap,
+	}
+}
+
+// History gives access to the HistoryFetcher syncs run, e.g. to tune PageSize
+func (s *Syncer) History() *HistoryFetcher { return s.history }
+
+// syncKey identifies a sync in the Checkpoint
+func (s *Syncer) syncKey(action, address string, contractAddress *string) string {
+	key := fmt.Sprintf("%d/%s/%s", s.client.chain.ID(), strings.ToLower(address), action)
+	if contractAddress != nil {
+		key += "/" + strings.ToLower(*contractAddress)
+	}
+	return key
+}
+
+// syncSpec describes how to sync one kind of record
+type syncSpec[T any] struct {
+	fetch windowFetch[T]
+	// id identifies a record within its block
+	id func(T) string
+	// blockHash of a record, nil when etherscan does not list it
+	blockHash func(T) string
+}
+
+// syncRecords fetches the records after the checkpoint of key, minus the overlap,
+// and hands those not handed out before to fn in ascending block order.
+// Unconfirmed records handed out before are checked first, see Syncer.ConfirmationDepth.
+//
+// When fn fails, the checkpoint is saved up to the record before and fn's error returned.
+func syncRecords[T interface{ GetBlockNumber() int }](ctx context.Context, s *Syncer, key string, spec syncSpec[T], fn func(T) error) error {
+	state, ok, err := s.checkpoint.Load(key)
+	if err != nil {
+		return errors.Wrap(err, "loading checkpoint")
+	}
+	if !ok {
+		state = SyncState{Block: s.StartBlock - 1}
+	}
+	if state.Seen == nil {
+		state.Seen = map[int]map[string]int{}
+	}
+
+	end, err := s.client.latestBlock(ctx)
+	if err != nil {
+		return err
+	}
+	from := s.from(state)
+	if from > end {
+		return nil
+	}
+
+	records, err := fetchHistory(ctx, s.history, from, &end, spec.fetch)
+	if err != nil {
+		return err
+	}
+	if err := retract(s, key, &state, records, spec); err != nil {
+		return err
+	}
+
+	// fetched counts the records of this sync per block and id,
+	// those beyond what state.Seen counts are new
+	fetched := map[int]map[string]int{}
+	for _, record := range records {
+		block, recordID := record.GetBlockNumber(), spec.id(record)
+		if fetched[block] == nil {
+			fetched[block] = map[string]int{}
+		}
+		fetched[block][recordID]++
+		if fetched[block][recordID] <= state.Seen[block][recordID] {
+			continue
+		}
+
+		if err := fn(record); err != nil {
+			state.Block = max(state.Block, block-1)
+			if saveErr := s.save(key, state, end); saveErr != nil {
+				return errors.Wrapf(saveErr, "saving checkpoint after %v", err)
+			}
+			return err
+		}
+		if state.Seen[block] == nil {
+			state.Seen[block] = map[string]int{}
+		}
+		state.Seen[block][recordID]++
+
+		if s.ConfirmationDepth > 0 && end-block < s.ConfirmationDepth {
+			content, err := json.Marshal(record)
+			if err != nil {
+				return errors.Wrap(err, "encoding unconfirmed record")
+			}
+			unconfirmed := UnconfirmedRecord{ID: recordID, Block: block, Record: content}
+			if spec.blockHash != nil {
+				unconfirmed.BlockHash = spec.blockHash(record)
+			}
+			state.Unconfirmed = append(state.Unconfirmed, unconfirmed)
+		}
+	}
+
+	state.Block = end
+	return s.save(key, state, end)
+}
+
+// retract checks the unconfirmed records of state against records, fetched anew,
+// and retracts those no longer found in the same block, latest first.
+func retract[T interface{ GetBlockNumber() int }](s *Syncer, key string, state *SyncState, records []T, spec syncSpec[T]) error {
+	if len(state.Unconfirmed) == 0 {
+		return nil
+	}
+
+	type placement struct {
+		id, blockHash string
+		block         int
+	}
+	current := map[placement]int{}
+	found := map[string]bool{}
+	for _, record := range records {
+		p := placement{id: spec.id(record), block: record.GetBlockNumber()}
+		if spec.blockHash != nil {
+			p.blockHash = spec.blockHash(record)
+		}
+		current[p]++
+		found[p.id] = true
+	}
+
+	var kept, gone []UnconfirmedRecord
+	for _, unconfirmed := range state.Unconfirmed {
+		p := placement{id: unconfirmed.ID, blockHash: unconfirmed.BlockHash, block: unconfirmed.Block}
+		if current[p] > 0 {
+			current[p]--
+			kept = append(kept, unconfirmed)
+			continue
+		}
+		gone = append(gone, unconfirmed)
+	}
+
+	for i := len(gone) - 1; i >= 0; i-- {
+		unconfirmed := gone[i]
+		var record T
+		if err := json.Unmarshal(unconfirmed.Record, &record); err != nil {
+			return errors.Wrapf(err, "decoding unconfirmed record %s", unconfirmed.ID)
+		}
+
+		retraction := Retraction{
+			Key:       key,
+			Reason:    RetractDisappeared,
+			ID:        unconfirmed.ID,
+			Block:     unconfirmed.Block,
+			BlockHash: unconfirmed.BlockHash,
+			Record:    record,
+		}
+		if found[unconfirmed.ID] {
+			retraction.Reason = RetractReorged
+		}
+		if s.OnRetract != nil {
+			if err := s.OnRetract(retraction); err != nil {
+				return err
+			}
+		}
+
+		// the record may be back elsewhere, and is to be handed out again then
+		if seen := state.Seen[unconfirmed.Block]; seen[unconfirmed.ID] > 1 {
+			seen[unconfirmed.ID]--
+		} else {
+			delete(seen, unconfirmed.ID)
+		}
+	}
+	state.Unconfirmed = kept
+	return nil
+}
+
+// from returns the first block a sync from state fetches
+func (s *Syncer) from(state SyncState) int {
+	from := state.Block + 1 - max(s.Overlap, 0)
+	for _, unconfirmed := range state.Unconfirmed {
+		from = min(from, unconfirmed.Block)
+	}
+	return max(from, s.StartBlock, 0)
+}
+
+// save stores state under key, forgetting records confirmed at head
+// and those below where the next sync starts
+func (s *Syncer) save(key string, state SyncState, head int) error {
+	unconfirmed := state.Unconfirmed[:0]
+	for _, record := range state.Unconfirmed {
+		if head-record.Block < s.ConfirmationDepth {
+			unconfirmed = append(unconfirmed, record)
+		}
+	}
+	state.Unconfirmed = unconfirmed
+
+	from := s.from(state)
+	for block := range state.Seen {
+		if block < from {
+			delete(state.Seen, block)
+		}
+	}
+	if err := s.checkpoint.Save(key, state); err != nil {
+		return errors.Wrap(err, "saving checkpoint")
+	}
+	return nil
+}
+
+// transferID identifies a token transfer, which etherscan does not reliably list with a log index
+func transferID(hash, contractAddress, from, to, amount string) string {
+	return strings.Join([]string{hash, strings.ToLower(contractAddress), strings.ToLower(from), strings.ToLower(to), amount}, "/")
+}
+
+// NormalTxs hands every "normal" tx of address not synced before to fn
+func (s *Syncer) NormalTxs(ctx context.Context, address string, fn func(response.NormalTx) error) error {
+	return syncRecords(ctx, s, s.syncKey("txlist", address, nil), syncSpec[response.NormalTx]{
+		fetch: func(ctx context.Context, start, end, page, offset int) ([]response.NormalTx, error) {
+			return s.client.NormalTxByAddressContext(ctx, address, &start, &end, page, offset, false)
+		},
+		id:        func(tx response.NormalTx) string { return tx.Hash },
+		blockHash: func(tx response.NormalTx) string { return tx.BlockHash },
+	}, fn)
+}
+
+// InternalTxs hands every "internal" tx of address not synced before to fn.
+// Internal txs come without block hash, only their block number is checked for reorgs.
+func (s *Syncer) InternalTxs(ctx context.Context, address string, fn func(response.InternalTx) error) error {
+	return syncRecords(ctx, s, s.syncKey("txlistinternal", address, nil), syncSpec[response.InternalTx]{
+		fetch: func(ctx context.Context, start, end, page, offset int) ([]response.InternalTx, error) {
+			return s.client.InternalTxByAddressContext(ctx, address, &start, &end, page, offset, false)
+		},
+		id: func(tx response.InternalTx) string { return tx.Hash + "/" + tx.TraceID },
+	}, fn)
+}
+
+// ERC20Transfers hands every erc20 transfer event of address not synced before to fn,
+// contractAddress optionally narrows them down to one token.
+func (s *Syncer) ERC20Transfers(ctx context.Context, contractAddress *string, address string, fn func(response.ERC20Transfer) error) error {
+	return syncRecords(ctx, s, s.syncKey("tokentx", address, contractAddress), syncSpec[response.ERC20Transfer]{
+		fetch: func(ctx context.Context, start, end, page, offset int) ([]response.ERC20Transfer, error) {
+			return s.client.ERC20TransfersContext(ctx, contractAddress, &address, &start, &end, page, offset, false)
+		},
+		id: func(tx response.ERC20Transfer) string {
+			return transferID(tx.Hash, tx.ContractAddress, tx.From, tx.To, tx.Value.Int().String())
+		},
+		blockHash: func(tx response.ERC20Transfer) string { return tx.BlockHash },
+	}, fn)
+}
+
+// ERC721Transfers hands every erc721 transfer event of address not synced before to fn,
+// see ERC20Transfers
+func (s *Syncer) ERC721Transfers(ctx context.Context, contractAddress *string, address string, fn func(response.ERC721Transfer) error) error {
+	return syncRecords(ctx, s, s.syncKey("tokennfttx", address, contractAddress), syncSpec[response.ERC721Transfer]{
+		fetch: func(ctx context.Context, start, end, page, offset int) ([]response.ERC721Transfer, error) {
+			return s.client.ERC721TransfersContext(ctx, contractAddress, &address, &start, &end, page, offset, false)
+		},
+		id: func(tx response.ERC721Transfer) string {
+			return transferID(tx.Hash, tx.ContractAddress, tx.From, tx.To, tx.TokenID.Int().String())
+		},
+		blockHash: func(tx response.ERC721Transfer) string { return tx.BlockHash },
+	}, fn)
+}
+
+// ERC1155Transfers hands every erc1155 transfer event of address not synced before to fn,
+// see ERC20Transfers
+func (s *Syncer) ERC1155Transfers(ctx context.Context, contractAddress *string, address string, fn func(response.ERC1155Transfer) error) error {
+	return syncRecords(ctx, s, s.syncKey("token1155tx", address, contractAddress), syncSpec[response.ERC1155Transfer]{
+		fetch: func(ctx context.Context, start, end, page, offset int) ([]response.ERC1155Transfer, error) {
+			return s.client.ERC1155TransfersContext(ctx, contractAddress, &address, &start, &end, page, offset, false)
+		},
+		id: func(tx response.ERC1155Transfer) string {
+			return transferID(tx.Hash, tx.ContractAddress, tx.From, tx.To, "")
+		},
+		blockHash: func(tx response.ERC1155Transfer) string { return tx.BlockHash },
+	}, fn)
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/pkg/response"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeChain serves txlist out of txs, with head as the latest block
+type fakeChain struct {
+	head int
+	txs  []map[string]string
+}
+
+func (f *fakeChain) add(block int, hash string) {
+	f.txs = append(f.txs, map[string]string{"blockNumber": strconv.Itoa(block), "timeStamp": "0", "hash": hash, "blockHash": fmt.Sprintf("0xb%d", block)})
+}
+
+// move reorgs tx hash into block, under a new block hash
+func (f *fakeChain) move(hash string, block int) {
+	for _, tx := range f.txs {
+		if tx["hash"] == hash {
+			tx["blockNumber"] = strconv.Itoa(block)
+			tx["blockHash"] = fmt.Sprintf("0xb%d'", block)
+		}
+	}
+}
+
+func (f *fakeChain) drop(hash string) {
+	f.txs = slices.DeleteFunc(f.txs, func(tx map[string]string) bool { return tx["hash"] == hash })
+}
+
+func (f *fakeChain) serve(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("action") == "getblocknobytime" {
+		fmt.Fprintf(w, `{"status":"1","message":"OK","result":"%d"}`, f.head)
+		return
+	}
+
+	start, _ := strconv.Atoi(q.Get("startblock"))
+	end, _ := strconv.Atoi(q.Get("endblock"))
+	var txs []map[string]string
+	for _, tx := range f.txs {
+		if block, _ := strconv.Atoi(tx["blockNumber"]); block >= start && block <= end {
+			txs = append(txs, tx)
+		}
+	}
+	if q.Get("page") != "1" {
+		txs = nil
+	}
+	result, _ := json.Marshal(txs)
+	fmt.Fprintf(w, `{"status":"1","message":"OK","result":%s}`, result)
+}
+
+func TestSyncer_NormalTxs(t *testing.T) {
+	chain := &fakeChain{head: 100}
+	chain.add(10, "0xa")
+	chain.add(50, "0xb")
+	chain.add(95, "0xc")
+	c := newTestClient(t, chain.serve)
+
+	s := NewSyncer(c, NewMemoryCheckpoint())
+	collect := func() []string {
+		var hashes []string
+		err := s.NormalTxs(context.Background(), "0xAbC", func(tx response.NormalTx) error {
+			hashes = append(hashes, tx.Hash)
+			return nil
+		})
+		assert.NoError(t, err)
+		return hashes
+	}
+
+	assert.Equal(t, []string{"0xa", "0xb", "0xc"}, collect())
+	assert.Empty(t, collect(), "nothing new")
+
+	// a late record inside the overlap and a new one
+	chain.head = 200
+	chain.add(90, "0xd")
+	chain.add(150, "0xe")
+	chain.add(150, "0xf")
+	assert.Equal(t, []string{"0xd", "0xe", "0xf"}, collect())
+
+	state, ok, err := s.checkpoint.Load(s.syncKey("txlist", "0xabc", nil))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 200, state.Block)
+	assert.NotContains(t, state.Seen, 50, "below the overlap")
+	assert.Equal(t, 1, state.Seen[150]["0xe"])
+}
+
+func TestSyncer_ResumesAfterError(t *testing.T) {
+	chain := &fakeChain{head: 100}
+	chain.add(10, "0xa")
+	chain.add(20, "0xb")
+	chain.add(30, "0xc")
+	c := newTestClient(t, chain.serve)
+
+	s := NewSyncer(c, NewMemoryCheckpoint())
+	s.Overlap = 0
+	failed := errors.New("consumer failed")
+
+	var hashes []string
+	err := s.NormalTxs(context.Background(), "0x1", func(tx response.NormalTx) error {
+		if tx.Hash == "0xb" {
+			return failed
+		}
+		hashes = append(hashes, tx.Hash)
+		return nil
+	})
+	assert.Equal(t, failed, err)
+
+	err = s.NormalTxs(context.Background(), "0x1", func(tx response.NormalTx) error {
+		hashes = append(hashes, tx.Hash)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0xa", "0xb", "0xc"}, hashes)
+}
+
+func TestSyncer_Reorg(t *testing.T) {
+	chain := &fakeChain{head: 100}
+	chain.add(50, "0xa")
+	chain.add(95, "0xb")
+	chain.add(98, "0xc")
+	c := newTestClient(t, chain.serve)
+
+	s := NewSyncer(c, NewMemoryCheckpoint())
+	s.Overlap = 0
+	s.ConfirmationDepth = 10
+
+	var events []string
+	s.OnRetract = func(r Retraction) error {
+		tx := r.Record.(response.NormalTx)
+		events = append(events, fmt.Sprintf("retract %s@%d %s", tx.Hash, r.Block, r.Reason))
+		return nil
+	}
+	sync := func() {
+		err := s.NormalTxs(context.Background(), "0x1", func(tx response.NormalTx) error {
+			events = append(events, fmt.Sprintf("add %s@%d", tx.Hash, tx.BlockNumber))
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	sync()
+	assert.Equal(t, []string{"add 0xa@50", "add 0xb@95", "add 0xc@98"}, events)
+
+	events = nil
+	chain.head = 102
+	chain.move("0xb", 97)
+	chain.drop("0xc")
+	sync()
+	assert.Equal(t, []string{"retract 0xc@98 disappeared", "retract 0xb@95 reorged", "add 0xb@97"}, events)
+
+	events = nil
+	chain.head = 120
+	sync()
+	assert.Empty(t, events)
+
+	state, _, err := s.checkpoint.Load(s.syncKey("txlist", "0x1", nil))
+	assert.NoError(t, err)
+	assert.Empty(t, state.Unconfirmed, "all final at 120")
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync", "checkpoint.json")
+	checkpoint, err := NewFileCheckpoint(path)
+	assert.NoError(t, err)
+
+	_, ok, err := checkpoint.Load("1/0xabc/txlist")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	state := SyncState{Block: 42, Seen: map[int]map[string]int{40: {"0xa": 1}}}
+	assert.NoError(t, checkpoint.Save("1/0xabc/txlist", state))
+	assert.NoError(t, checkpoint.Save("1/0xabc/tokentx", SyncState{Block: 7}))
+
+	reopened, err := NewFileCheckpoint(path)
+	assert.NoError(t, err)
+	got, ok, err := reopened.Load("1/0xabc/txlist")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, state, got)
+}
@@ -8,6 +8,7 @@
 package client
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/TokenTax/etherscan-api/v2/pkg/response"
@@ -31,28 +32,33 @@ func (p TransactionParams) GetUrlValues() url.Values {
 
 // ExecutionStatus checks contract execution status
 func (c *Client) ExecutionStatus(txHash string) (response.ExecutionStatus, error) {
+	return c.ExecutionStatusContext(context.Background(), txHash)
+}
+
+// ExecutionStatusContext is like ExecutionStatus but bound to ctx
+func (c *Client) ExecutionStatusContext(ctx context.Context, txHash string) (response.ExecutionStatus, error) {
 	param := TransactionParams{TxHash: txHash}
 
-	body, err := c.execute("transaction", "getstatus", param.GetUrlValues())
+	result, err := call[response.ExecutionStatus](ctx, c, "transaction", "getstatus", param.GetUrlValues())
 	if err != nil {
 		return response.ExecutionStatus{}, errors.Wrap(err, "executing ExecutionStatus request")
 	}
-	return response.ReadResponse[response.ExecutionStatus](body)
+	return result, nil
 }
 
 // ReceiptStatus checks transaction receipt status
 func (c *Client) ReceiptStatus(txHash string) (int, error) {
+	return c.ReceiptStatusContext(context.Background(), txHash)
+}
+
+// ReceiptStatusContext is like ReceiptStatus but bound to ctx
+func (c *Client) ReceiptStatusContext(ctx context.Context, txHash string) (int, error) {
 	param := TransactionParams{TxHash: txHash}
-	body, err := c.execute("transaction", "gettxreceiptstatus", param.GetUrlValues())
+	rawStatus, err := call[response.StatusReponse](ctx, c, "transaction", "gettxreceiptstatus", param.GetUrlValues())
 	if err != nil {
 		return 0, errors.Wrap(err, "executing ReceiptStatus request")
 	}
 
-	rawStatus, err := response.ReadResponse[response.StatusReponse](body)
-	if err != nil {
-		return 0, err
-	}
-
 	switch rawStatus.Status {
 	case "0":
 		return 0, nil
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Sentinel kinds of Error, use them with errors.Is
+var (
+	ErrRateLimited          = errors.New("rate limited")
+	ErrInvalidAPIKey        = errors.New("invalid API key")
+	ErrResultWindowExceeded = errors.New("result window is too large")
+	ErrTimeout              = errors.New("query timeout")
+	ErrInvalidParams        = errors.New("invalid parameters")
+	ErrProOnly              = errors.New("endpoint requires a paid plan")
+	ErrNotFound             = errors.New("not found")
+)
+
+// Error is a failed etherscan call, either a status 0 envelope
+// or a non-200 HTTP response. Use errors.As to get at it.
+type Error struct {
+	// Kind is one of the sentinel errors above, nil if unrecognized
+	Kind error
+	// Message is the envelope message, usually "NOTOK",
+	// or the HTTP status text for non-200 responses
+	Message string
+	// Result is the envelope result text, which carries the details,
+	// or the body for non-200 responses
+	Result string
+	// Module and Action of the request
+	Module string
+	Action string
+	// HTTPStatus of the response
+	HTTPStatus int
+	// RetryAfter from the Retry-After header, zero when absent
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.HTTPStatus != 0 && e.HTTPStatus != http.StatusOK {
+		return fmt.Sprintf("got non-200 status code; status: %v, status text: %s, response body: %s", e.HTTPStatus, e.Message, e.Result)
+	}
+	if e.Result != "" && e.Result != e.Message {
+		return fmt.Sprintf("etherscan server: %s, %s", e.Message, e.Result)
+	}
+	return fmt.Sprintf("etherscan server: %s", e.Message)
+}
+
+// Unwrap returns Kind, so errors.Is(err, ErrRateLimited) and friends work
+func (e *Error) Unwrap() error { return e.Kind }
+
+// errorKinds maps lower-cased fragments of etherscan messages to kinds,
+// first match wins.
+var errorKinds = []struct {
+	fragment string
+	kind     error
+}{
+	{"rate limit", ErrRateLimited},
+	{"api key", ErrInvalidAPIKey},
+	{"apikey", ErrInvalidAPIKey},
+	{"result window is too large", ErrResultWindowExceeded},
+	{"timeout", ErrTimeout},
+	{"timed out", ErrTimeout},
+	{"api pro", ErrProOnly},
+	{"pro endpoint", ErrProOnly},
+	{"upgrade", ErrProOnly},
+	{"not supported for this chain", ErrProOnly},
+	{"no transactions found", ErrNotFound},
+	{"no records found", ErrNotFound},
+	{"no data found", ErrNotFound},
+	{"not verified", ErrNotFound},
+	{"invalid", ErrInvalidParams},
+	{"missing", ErrInvalidParams},
+}
+
+// classify guesses the kind of an envelope error from its message and result
+func classify(message, result string) error {
+	text := strings.ToLower(message + " " + result)
+	for _, k := range errorKinds {
+		if strings.Contains(text, k.fragment) {
+			return k.kind
+		}
+	}
+	return nil
+}
+
+// NewHTTPError builds the Error for a non-200 HTTP response
+func NewHTTPError(statusCode int, status, body string, retryAfter time.Duration) *Error {
+	var kind error
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case http.StatusNotFound:
+		kind = ErrNotFound
+	case http.StatusGatewayTimeout:
+		kind = ErrTimeout
+	}
+	return &Error{
+		Kind:       kind,
+		Message:    status,
+		Result:     body,
+		HTTPStatus: statusCode,
+		RetryAfter: retryAfter,
+	}
+}
@@ -0,0 +1,89 @@
+package response
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/TokenTax/etherscan-api/v2/internal/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadResponse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		kind   error
+		result string
+	}{
+		{
+			name:   "rate limited",
+			body:   `{"status":"0","message":"NOTOK","result":"Max calls per sec rate limit reached (5/sec)"}`,
+			kind:   ErrRateLimited,
+			result: "Max calls per sec rate limit reached (5/sec)",
+		},
+		{
+			name:   "invalid key",
+			body:   `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`,
+			kind:   ErrInvalidAPIKey,
+			result: "Invalid API Key",
+		},
+		{
+			name:   "result window",
+			body:   `{"status":"0","message":"NOTOK","result":"Result window is too large, PageNo x Offset size must be less than or equal to 10000"}`,
+			kind:   ErrResultWindowExceeded,
+			result: "Result window is too large, PageNo x Offset size must be less than or equal to 10000",
+		},
+		{
+			name:   "timeout",
+			body:   `{"status":"0","message":"NOTOK","result":"Query Timeout occured. Please select a smaller result dataset"}`,
+			kind:   ErrTimeout,
+			result: "Query Timeout occured. Please select a smaller result dataset",
+		},
+		{
+			name:   "invalid params",
+			body:   `{"status":"0","message":"NOTOK","result":"Error! Invalid address format"}`,
+			kind:   ErrInvalidParams,
+			result: "Error! Invalid address format",
+		},
+		{
+			name:   "pro only",
+			body:   `{"status":"0","message":"NOTOK","result":"Sorry, it looks like you are trying to access an API Pro endpoint. Contact us to upgrade to API Pro."}`,
+			kind:   ErrProOnly,
+			result: "Sorry, it looks like you are trying to access an API Pro endpoint. Contact us to upgrade to API Pro.",
+		},
+		{
+			name:   "not found",
+			body:   `{"status":"0","message":"No transactions found","result":[]}`,
+			kind:   ErrNotFound,
+			result: "[]",
+		},
+		{
+			name:   "unrecognized",
+			body:   `{"status":"0","message":"NOTOK","result":"Something else"}`,
+			result: "Something else",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadResponse[types.BigInt](*bytes.NewBufferString(tt.body))
+
+			var apiErr *Error
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.kind, apiErr.Kind)
+			assert.Equal(t, tt.result, apiErr.Result)
+			if tt.kind != nil {
+				assert.ErrorIs(t, err, tt.kind)
+			}
+		})
+	}
+}
+
+func TestNewHTTPError(t *testing.T) {
+	err := NewHTTPError(http.StatusTooManyRequests, "429 Too Many Requests", "slow down", 0)
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, "got non-200 status code; status: 429, status text: 429 Too Many Requests, response body: slow down", err.Error())
+}
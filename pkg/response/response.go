@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,10 @@ type EtherscanResponse interface {
 		ERC721Transfer | []ERC721Transfer |
 		ERC1155Transfer | []ERC1155Transfer |
 		MinedBlock | []MinedBlock |
+		BeaconWithdrawal | []BeaconWithdrawal |
+		TokenHolding | []TokenHolding |
+		NFTHolding | []NFTHolding |
+		NFTInventoryItem | []NFTInventoryItem |
 		ContractSource | []ContractSource |
 		ExecutionStatus | []ExecutionStatus |
 		BlockRewards | []BlockRewards |
@@ -37,37 +42,53 @@ type EtherscanResponse interface {
 }
 
 // envelope is the carrier of nearly every response
-type envelope[T EtherscanResponse] struct {
+type envelope struct {
 	// 1 for good, 0 for error
 	Status int `json:"status,string"`
 	// OK for good, other words when Status equals 0
 	Message string `json:"message"`
 	// where response lies
-	Result T `json:"result"`
-}
-
-type statusEnvelope struct {
-	Status int `json:"status,string"`
+	Result json.RawMessage `json:"result"`
 }
 
+// ReadResponse decodes an etherscan envelope, returning its result as T,
+// or an *Error when status is not 1.
+//
+// The *Error has its Kind guessed from the message and result text,
+// Module, Action and HTTPStatus are left for the caller to fill in.
 func ReadResponse[T EtherscanResponse](content bytes.Buffer) (T, error) {
 	var ret T
 
-	var envelope envelope[T]
+	var envelope envelope
 	if err := json.Unmarshal(content.Bytes(), &envelope); err != nil {
-		var statusEnv statusEnvelope
-		if err := json.Unmarshal(content.Bytes(), &statusEnv); err == nil {
-			if statusEnv.Status != 1 {
-				return ret, errors.Errorf("unmarshaling response into %T; message=%s", ret, envelope.Message)
-			}
-		}
 		return ret, errors.Wrapf(err, "unmarshaling etherscan response; body=%s", content.Bytes())
 	}
 	if envelope.Status != 1 {
-		return ret, errors.Errorf("etherscan server: %s", envelope.Message)
+		text := resultText(envelope.Result)
+		return ret, &Error{
+			Kind:    classify(envelope.Message, text),
+			Message: envelope.Message,
+			Result:  text,
+		}
 	}
 
-	return envelope.Result, nil
+	if err := json.Unmarshal(envelope.Result, &ret); err != nil {
+		return ret, errors.Wrapf(err, "unmarshaling response into %T; message=%s", ret, envelope.Message)
+	}
+	return ret, nil
+}
+
+// resultText returns result as text, which is where etherscan puts
+// error details like "Max rate limit reached"
+func resultText(result json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(result, &text); err != nil {
+		if string(result) == "null" {
+			return ""
+		}
+		return string(result)
+	}
+	return text
 }
 
 // AccountBalance account and its balance in pair
@@ -129,19 +150,21 @@ type (
 
 	// ERC20Transfer holds info from ERC20 token transfer event query
 	ERC20Transfer struct {
-		BlockNumber       int           `json:"blockNumber,string"`
-		TimeStamp         types.Time    `json:"timeStamp"`
-		Hash              string        `json:"hash"`
-		Nonce             int           `json:"nonce,string"`
-		BlockHash         string        `json:"blockHash"`
-		From              string        `json:"from"`
-		ContractAddress   string        `json:"contractAddress"`
-		To                string        `json:"to"`
-		Value             *types.BigInt `json:"value"`
-		TokenName         string        `json:"tokenName"`
-		TokenSymbol       string        `json:"tokenSymbol"`
-		TokenDecimal      int           `json:"tokenDecimal,string"`
-		TransactionIndex  int           `json:"transactionIndex,string"`
+		BlockNumber      int           `json:"blockNumber,string"`
+		TimeStamp        types.Time    `json:"timeStamp"`
+		Hash             string        `json:"hash"`
+		Nonce            int           `json:"nonce,string"`
+		BlockHash        string        `json:"blockHash"`
+		From             string        `json:"from"`
+		ContractAddress  string        `json:"contractAddress"`
+		To               string        `json:"to"`
+		Value            *types.BigInt `json:"value"`
+		TokenName        string        `json:"tokenName"`
+		TokenSymbol      string        `json:"tokenSymbol"`
+		TokenDecimal     int           `json:"tokenDecimal,string"`
+		TransactionIndex int           `json:"transactionIndex,string"`
+		// LogIndex is zero when etherscan leaves it out
+		LogIndex          int           `json:"logIndex,string"`
 		Gas               int           `json:"gas,string"`
 		GasPrice          *types.BigInt `json:"gasPrice"`
 		GasUsed           int           `json:"gasUsed,string"`
@@ -152,19 +175,21 @@ type (
 
 	// ERC721Transfer holds info from ERC721 token transfer event query
 	ERC721Transfer struct {
-		BlockNumber       int           `json:"blockNumber,string"`
-		TimeStamp         types.Time    `json:"timeStamp"`
-		Hash              string        `json:"hash"`
-		Nonce             int           `json:"nonce,string"`
-		BlockHash         string        `json:"blockHash"`
-		From              string        `json:"from"`
-		ContractAddress   string        `json:"contractAddress"`
-		To                string        `json:"to"`
-		TokenID           *types.BigInt `json:"tokenID"`
-		TokenName         string        `json:"tokenName"`
-		TokenSymbol       string        `json:"tokenSymbol"`
-		TokenDecimal      int           `json:"tokenDecimal,string"`
-		TransactionIndex  int           `json:"transactionIndex,string"`
+		BlockNumber      int           `json:"blockNumber,string"`
+		TimeStamp        types.Time    `json:"timeStamp"`
+		Hash             string        `json:"hash"`
+		Nonce            int           `json:"nonce,string"`
+		BlockHash        string        `json:"blockHash"`
+		From             string        `json:"from"`
+		ContractAddress  string        `json:"contractAddress"`
+		To               string        `json:"to"`
+		TokenID          *types.BigInt `json:"tokenID"`
+		TokenName        string        `json:"tokenName"`
+		TokenSymbol      string        `json:"tokenSymbol"`
+		TokenDecimal     int           `json:"tokenDecimal,string"`
+		TransactionIndex int           `json:"transactionIndex,string"`
+		// LogIndex is zero when etherscan leaves it out
+		LogIndex          int           `json:"logIndex,string"`
 		Gas               int           `json:"gas,string"`
 		GasPrice          *types.BigInt `json:"gasPrice"`
 		GasUsed           int           `json:"gasUsed,string"`
@@ -188,7 +213,9 @@ type (
 		TokenSymbol string `json:"tokenSymbol"`
 		//TokenDecimal      int     `json:"tokenDecimal,string"`
 		//TokenValue int `json:"tokenValue,string"`
-		//TransactionIndex  int     `json:"transactionIndex,string"`
+		TransactionIndex int `json:"transactionIndex,string"`
+		// LogIndex is zero when etherscan leaves it out
+		LogIndex int `json:"logIndex,string"`
 		//Gas               int     `json:"gas,string"`
 		//GasPrice *BigInt `json:"gasPrice"`
 		//GasUsed           int     `json:"gasUsed,string"`
@@ -220,6 +247,69 @@ type MinedBlock struct {
 	BlockReward *types.BigInt `json:"blockReward"`
 }
 
+// BeaconWithdrawal holds info from query for beacon chain withdrawals to address
+type BeaconWithdrawal struct {
+	WithdrawalIndex int    `json:"withdrawalIndex,string"`
+	ValidatorIndex  int    `json:"validatorIndex,string"`
+	Address         string `json:"address"`
+	// Amount is in wei, etherscan reports it in gwei
+	Amount      *types.BigInt `json:"amount"`
+	BlockNumber int           `json:"blockNumber,string"`
+	TimeStamp   types.Time    `json:"timestamp"`
+}
+
+// weiPerGwei scales BeaconWithdrawal.Amount
+var weiPerGwei = big.NewInt(1e9)
+
+// beaconWithdrawalJSON keeps BeaconWithdrawal's tags without its methods
+type beaconWithdrawalJSON BeaconWithdrawal
+
+// UnmarshalJSON implements json.Unmarshaler, converting the amount to wei
+func (w *BeaconWithdrawal) UnmarshalJSON(data []byte) error {
+	var raw beaconWithdrawalJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Amount != nil {
+		raw.Amount.Int().Mul(raw.Amount.Int(), weiPerGwei)
+	}
+	*w = BeaconWithdrawal(raw)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the amount back in gwei
+func (w BeaconWithdrawal) MarshalJSON() ([]byte, error) {
+	raw := beaconWithdrawalJSON(w)
+	if w.Amount != nil {
+		raw.Amount = (*types.BigInt)(new(big.Int).Quo(w.Amount.Int(), weiPerGwei))
+	}
+	return json.Marshal(raw)
+}
+
+// TokenHolding holds info from query for erc20 tokens held by address
+type TokenHolding struct {
+	TokenAddress  string        `json:"TokenAddress"`
+	TokenName     string        `json:"TokenName"`
+	TokenSymbol   string        `json:"TokenSymbol"`
+	TokenQuantity *types.BigInt `json:"TokenQuantity"`
+	// TokenDecimal is the number of decimals of TokenQuantity, etherscan calls it divisor
+	TokenDecimal int `json:"TokenDivisor,string"`
+}
+
+// NFTHolding holds info from query for erc721 tokens held by address, per contract
+type NFTHolding struct {
+	TokenAddress  string        `json:"TokenAddress"`
+	TokenName     string        `json:"TokenName"`
+	TokenSymbol   string        `json:"TokenSymbol"`
+	TokenQuantity *types.BigInt `json:"TokenQuantity"`
+}
+
+// NFTInventoryItem holds info from query for erc721 token ids held by address
+type NFTInventoryItem struct {
+	TokenAddress string        `json:"TokenAddress"`
+	TokenID      *types.BigInt `json:"TokenId"`
+}
+
 // ContractSource holds info from query for contract source code
 type ContractSource struct {
 	SourceCode           string `json:"SourceCode"`
@@ -266,15 +356,123 @@ type LatestPrice struct {
 	ETHUSDTimestamp types.Time `json:"ethusd_timestamp"`
 }
 
+// Log holds info from query for event logs.
+//
+// Etherscan gives the numbers of a log as hex quantities, they are parsed on decoding.
 type Log struct {
-	Address         string   `json:"address"`
-	Topics          []string `json:"topics"`
-	Data            string   `json:"data"`
-	BlockNumber     string   `json:"blockNumber"`
-	TransactionHash string   `json:"transactionHash"`
-	BlockHash       string   `json:"blockHash"`
-	LogIndex        string   `json:"logIndex"`
-	Removed         bool     `json:"removed"`
+	Address          string        `json:"address"`
+	Topics           []string      `json:"topics"`
+	Data             string        `json:"data"`
+	BlockNumber      uint64        `json:"blockNumber"`
+	TimeStamp        types.Time    `json:"timeStamp"`
+	GasPrice         *types.BigInt `json:"gasPrice"`
+	GasUsed          *types.BigInt `json:"gasUsed"`
+	LogIndex         uint64        `json:"logIndex"`
+	TransactionHash  string        `json:"transactionHash"`
+	TransactionIndex uint64        `json:"transactionIndex"`
+	BlockHash        string        `json:"blockHash"`
+	Removed          bool          `json:"removed"`
+}
+
+// logJSON is Log as etherscan encodes it
+type logJSON struct {
+	Address          string   `json:"address"`
+	Topics           []string `json:"topics"`
+	Data             string   `json:"data"`
+	BlockNumber      string   `json:"blockNumber"`
+	TimeStamp        string   `json:"timeStamp,omitempty"`
+	GasPrice         string   `json:"gasPrice,omitempty"`
+	GasUsed          string   `json:"gasUsed,omitempty"`
+	LogIndex         string   `json:"logIndex"`
+	TransactionHash  string   `json:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex"`
+	BlockHash        string   `json:"blockHash"`
+	Removed          bool     `json:"removed"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, parsing the hex quantities
+func (l *Log) UnmarshalJSON(data []byte) error {
+	var raw logJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*l = Log{
+		Address:         raw.Address,
+		Topics:          raw.Topics,
+		Data:            raw.Data,
+		TransactionHash: raw.TransactionHash,
+		BlockHash:       raw.BlockHash,
+		Removed:         raw.Removed,
+	}
+
+	var err error
+	if l.BlockNumber, err = types.ParseQuantity(raw.BlockNumber); err != nil {
+		return errors.Wrap(err, "blockNumber")
+	}
+	if l.LogIndex, err = types.ParseQuantity(raw.LogIndex); err != nil {
+		return errors.Wrap(err, "logIndex")
+	}
+	if l.TransactionIndex, err = types.ParseQuantity(raw.TransactionIndex); err != nil {
+		return errors.Wrap(err, "transactionIndex")
+	}
+	if raw.TimeStamp != "" {
+		if err := l.TimeStamp.UnmarshalText([]byte(raw.TimeStamp)); err != nil {
+			return errors.Wrap(err, "timeStamp")
+		}
+	}
+	if l.GasPrice, err = hexBigInt(raw.GasPrice); err != nil {
+		return errors.Wrap(err, "gasPrice")
+	}
+	if l.GasUsed, err = hexBigInt(raw.GasUsed); err != nil {
+		return errors.Wrap(err, "gasUsed")
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the numbers back as hex quantities
+func (l Log) MarshalJSON() ([]byte, error) {
+	raw := logJSON{
+		Address:          l.Address,
+		Topics:           l.Topics,
+		Data:             l.Data,
+		BlockNumber:      hexQuantity(l.BlockNumber),
+		GasPrice:         bigIntHexQuantity(l.GasPrice),
+		GasUsed:          bigIntHexQuantity(l.GasUsed),
+		LogIndex:         hexQuantity(l.LogIndex),
+		TransactionHash:  l.TransactionHash,
+		TransactionIndex: hexQuantity(l.TransactionIndex),
+		BlockHash:        l.BlockHash,
+		Removed:          l.Removed,
+	}
+	if !l.TimeStamp.Time().IsZero() {
+		raw.TimeStamp = hexQuantity(uint64(l.TimeStamp.Time().Unix()))
+	}
+	return json.Marshal(raw)
+}
+
+func hexQuantity(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+// hexBigInt parses a hex quantity, nil when text is empty
+func hexBigInt(text string) (*types.BigInt, error) {
+	if text == "" {
+		return nil, nil
+	}
+	n := new(types.BigInt)
+	if err := n.UnmarshalText([]byte(text)); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// bigIntHexQuantity is hexQuantity for n, empty when n is nil
+func bigIntHexQuantity(n *types.BigInt) string {
+	if n == nil {
+		return ""
+	}
+	return "0x" + n.Int().Text(16)
 }
 
 // GasPrices holds info for Gas Oracle queries
@@ -0,0 +1,70 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_JSON(t *testing.T) {
+	const raw = `{
+		"address": "0xbd3531da5cf5857e7cfaa92426877b022e612cf8",
+		"topics": ["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],
+		"data": "0x",
+		"blockNumber": "0xc48174",
+		"timeStamp": "0x60f9ce56",
+		"gasPrice": "0x2e90edd000",
+		"gasUsed": "0x247205",
+		"logIndex": "0x",
+		"transactionHash": "0x4ffd22d986913d33927a392fe4319bcd2b62f3afe1c15a2c59f77fc2cc4c20a9",
+		"transactionIndex": "0x1b"
+	}`
+
+	var log Log
+	assert.NoError(t, json.Unmarshal([]byte(raw), &log))
+	assert.Equal(t, uint64(12878196), log.BlockNumber)
+	assert.Equal(t, int64(1626984022), log.TimeStamp.Time().Unix())
+	assert.Equal(t, "200000000000", log.GasPrice.Int().String())
+	assert.Equal(t, "2388485", log.GasUsed.Int().String())
+	assert.Zero(t, log.LogIndex)
+	assert.Equal(t, uint64(27), log.TransactionIndex)
+
+	encoded, err := json.Marshal(log)
+	assert.NoError(t, err)
+	// written back in the wire format
+	for _, field := range []string{`"blockNumber":"0xc48174"`, `"timeStamp":"0x60f9ce56"`, `"gasPrice":"0x2e90edd000"`, `"gasUsed":"0x247205"`, `"logIndex":"0x0"`, `"transactionIndex":"0x1b"`} {
+		assert.Contains(t, string(encoded), field)
+	}
+	var again Log
+	assert.NoError(t, json.Unmarshal(encoded, &again))
+	assert.Equal(t, log, again)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"blockNumber":"0xzz"}`), &log))
+}
+
+func TestBeaconWithdrawal_JSON(t *testing.T) {
+	const raw = `{
+		"withdrawalIndex": "13",
+		"validatorIndex": "117823",
+		"address": "0xb9d7934878b5fb9610b3fe8a5e441e8fad7e293f",
+		"amount": "3402931175",
+		"blockNumber": "17034877",
+		"timestamp": "1681338599"
+	}`
+
+	var withdrawal BeaconWithdrawal
+	assert.NoError(t, json.Unmarshal([]byte(raw), &withdrawal))
+	assert.Equal(t, 13, withdrawal.WithdrawalIndex)
+	assert.Equal(t, 117823, withdrawal.ValidatorIndex)
+	assert.Equal(t, "3402931175000000000", withdrawal.Amount.Int().String())
+	assert.Equal(t, 17034877, withdrawal.BlockNumber)
+	assert.Equal(t, int64(1681338599), withdrawal.TimeStamp.Time().Unix())
+
+	encoded, err := json.Marshal(withdrawal)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"amount":"3402931175"`)
+	var again BeaconWithdrawal
+	assert.NoError(t, json.Unmarshal(encoded, &again))
+	assert.Equal(t, withdrawal, again)
+}
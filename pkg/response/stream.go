@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2018 LI Zhennan
+ *
+ * Use of this work is governed by a MIT License.
+ * You may find a license copy in project root.
+ */
+
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// StreamResponse decodes an etherscan envelope from r like ReadResponse,
+// but hands the elements of its result array to fn one by one
+// instead of building a slice of them.
+//
+// Decoding stops at the first error fn returns, which StreamResponse returns as is.
+// An envelope whose status is not 1 yields an *Error, as with ReadResponse.
+func StreamResponse[T any](r io.Reader, fn func(T) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return errors.Wrap(err, "reading etherscan response")
+	}
+
+	var (
+		env        envelope
+		statusSeen bool
+		streamed   bool
+		// pending holds the elements of a result that came before status
+		pending []json.RawMessage
+		// text holds a result that is not a list, e.g. error details
+		text    string
+		notList bool
+	)
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return errors.Wrap(err, "reading etherscan response")
+		}
+		key, _ := token.(string)
+
+		switch key {
+		case "status":
+			var status string
+			if err := dec.Decode(&status); err != nil {
+				return errors.Wrap(err, "reading status of etherscan response")
+			}
+			if env.Status, err = strconv.Atoi(status); err != nil {
+				return errors.Wrapf(err, "reading status of etherscan response; status=%s", status)
+			}
+			statusSeen = true
+		case "message":
+			if err := dec.Decode(&env.Message); err != nil {
+				return errors.Wrap(err, "reading message of etherscan response")
+			}
+		case "result":
+			token, err := dec.Token()
+			if err != nil {
+				return errors.Wrap(err, "reading result of etherscan response")
+			}
+			switch token {
+			case json.Delim('['):
+				if statusSeen && env.Status == 1 {
+					if err := streamArray(dec, fn); err != nil {
+						return err
+					}
+					streamed = true
+					break
+				}
+				for dec.More() {
+					var raw json.RawMessage
+					if err := dec.Decode(&raw); err != nil {
+						return errors.Wrap(err, "reading result of etherscan response")
+					}
+					pending = append(pending, raw)
+				}
+				if err := expectDelim(dec, ']'); err != nil {
+					return errors.Wrap(err, "reading result of etherscan response")
+				}
+			case json.Delim('{'):
+				object := map[string]json.RawMessage{}
+				for dec.More() {
+					field, err := dec.Token()
+					if err != nil {
+						return errors.Wrap(err, "reading result of etherscan response")
+					}
+					var raw json.RawMessage
+					if err := dec.Decode(&raw); err != nil {
+						return errors.Wrap(err, "reading result of etherscan response")
+					}
+					object[fmt.Sprint(field)] = raw
+				}
+				if err := expectDelim(dec, '}'); err != nil {
+					return errors.Wrap(err, "reading result of etherscan response")
+				}
+				raw, _ := json.Marshal(object)
+				text, notList = string(raw), true
+			case nil:
+				notList = true
+			default:
+				text, notList = fmt.Sprint(token), true
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return errors.Wrap(err, "reading etherscan response")
+			}
+		}
+	}
+
+	if env.Status != 1 {
+		return &Error{
+			Kind:    classify(env.Message, text),
+			Message: env.Message,
+			Result:  text,
+		}
+	}
+	if streamed {
+		return nil
+	}
+	if notList {
+		return errors.Errorf("etherscan result is not a list; message=%s, result=%s", env.Message, text)
+	}
+
+	for i, raw := range pending {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return errors.Wrapf(err, "unmarshaling result element %d into %T", i, item)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamArray decodes the elements of the array dec just entered one by one into fn
+func streamArray[T any](dec *json.Decoder, fn func(T) error) error {
+	for i := 0; dec.More(); i++ {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return errors.Wrapf(err, "unmarshaling result element %d into %T", i, item)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return errors.Wrap(err, "reading result of etherscan response")
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return errors.Errorf("expected %v, got %v", want, token)
+	}
+	return nil
+}
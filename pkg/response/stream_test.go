@@ -0,0 +1,87 @@
+package response
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamResponse(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+
+	tests := []struct {
+		name  string
+		body  string
+		items []int
+		kind  error
+	}{
+		{
+			name:  "streamed",
+			body:  `{"status":"1","message":"OK","result":[{"n":1},{"n":2},{"n":3}]}`,
+			items: []int{1, 2, 3},
+		},
+		{
+			name:  "result before status",
+			body:  `{"result":[{"n":4}],"message":"OK","status":"1"}`,
+			items: []int{4},
+		},
+		{
+			name: "empty",
+			body: `{"status":"1","message":"OK","result":[]}`,
+		},
+		{
+			name: "api error",
+			body: `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`,
+			kind: ErrInvalidAPIKey,
+		},
+		{
+			name: "no records",
+			body: `{"status":"0","message":"No transactions found","result":[]}`,
+			kind: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			err := StreamResponse(strings.NewReader(tt.body), func(it item) error {
+				got = append(got, it.N)
+				return nil
+			})
+			if tt.kind != nil {
+				assert.ErrorIs(t, err, tt.kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.items, got)
+		})
+	}
+}
+
+func TestStreamResponse_Stop(t *testing.T) {
+	stop := errors.New("stop")
+	var got []int
+	err := StreamResponse(strings.NewReader(`{"status":"1","message":"OK","result":[1,2,3,`), func(n int) error {
+		got = append(got, n)
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []int{1, 2}, got)
+}
+
+func TestStreamResponse_Malformed(t *testing.T) {
+	var got []int
+	err := StreamResponse(strings.NewReader(`{"status":"1","message":"OK","result":[1,2,"x"]}`), func(n int) error {
+		got = append(got, n)
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, got)
+}